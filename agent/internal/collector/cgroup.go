@@ -0,0 +1,404 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("cgroup", func(cfg config.Config) Collector {
+		return &cgroupCollector{root: cfg.CgroupRoot()}
+	})
+}
+
+var (
+	// Leaf cgroups of containers: "<id>", "docker-<id>.scope", "cri-containerd-<id>.scope", "crio-<id>.scope"
+	containerIDPattern = regexp.MustCompile(`^(?:[a-z-]+-)?([0-9a-f]{64})(?:\.scope)?$`)
+	// Pod cgroups: "pod<uid>" (cgroupfs driver) or "kubepods-...-pod<uid_with_underscores>.slice" (systemd driver)
+	podUIDPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+)
+
+// v1 memory and blkio report this (page aligned int64 max) when there is no limit
+const cgroupV1Unlimited = 1 << 62
+
+type containerCgroup struct {
+	id     string
+	podUID string
+	path   string // relative to the hierarchy root
+}
+
+type cgroupSnapshot struct {
+	cpuSeconds float64
+	readBytes  uint64
+	writeBytes uint64
+	taken      time.Time
+}
+
+// cgroupCollector reports per-container CPU, memory and block I/O read from the
+// cgroup filesystem. Both the v2 unified hierarchy and v1 controllers are supported.
+type cgroupCollector struct {
+	root string
+
+	mu   sync.Mutex
+	prev map[string]cgroupSnapshot
+}
+
+func (c *cgroupCollector) Name() string { return "cgroup" }
+
+func (c *cgroupCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect cgroup stats...", logGoroutineInfo())
+
+	reader := newCgroupReader(c.root)
+	containers, err := findContainers(ctx, reader.discoveryDir())
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]*pb.ContainerUsage, 0, len(containers))
+	for _, container := range containers {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		usages = append(usages, reader.read(container))
+	}
+
+	metric := &pb.Metric{ContainerStats: c.update(usages, time.Now())}
+
+	for _, stats := range metric.ContainerStats {
+		log.Printf("%s: Container: %.12s (pod %s), CPU: %.2f%%, Memory: %d/%d bytes, OOM kills: %d\n",
+			logGoroutineInfo(), stats.ContainerId, stats.PodUid, stats.CpuUsagePercent,
+			stats.MemoryUsageBytes, stats.MemoryLimitBytes, stats.OomKills)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("cgroup_version", reader.version),
+		attribute.Int("containers_processed", len(metric.ContainerStats)),
+	)
+
+	return metric, nil
+}
+
+// update adds rates relative to the previous snapshot of the same cgroup.
+// New or restarted containers only report raw counters this cycle.
+func (c *cgroupCollector) update(usages []*pb.ContainerUsage, now time.Time) []*pb.ContainerUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := make(map[string]cgroupSnapshot, len(usages))
+	for _, usage := range usages {
+		curr := cgroupSnapshot{
+			cpuSeconds: usage.CpuUsageSeconds,
+			readBytes:  usage.IoReadBytes,
+			writeBytes: usage.IoWriteBytes,
+			taken:      now,
+		}
+		next[usage.CgroupPath] = curr
+
+		if prev, ok := c.prev[usage.CgroupPath]; ok {
+			applyCgroupRates(usage, prev, curr)
+		}
+	}
+
+	c.prev = next
+	return usages
+}
+
+func applyCgroupRates(usage *pb.ContainerUsage, prev, curr cgroupSnapshot) {
+	seconds := curr.taken.Sub(prev.taken).Seconds()
+	if seconds <= 0 || curr.cpuSeconds < prev.cpuSeconds {
+		return
+	}
+	readBytes, ok1 := counterDelta(prev.readBytes, curr.readBytes)
+	writeBytes, ok2 := counterDelta(prev.writeBytes, curr.writeBytes)
+	if !(ok1 && ok2) {
+		// Cgroup was re-created under the same path, rates resume next cycle
+		return
+	}
+
+	usage.RateIntervalSeconds = seconds
+	usage.CpuUsagePercent = (curr.cpuSeconds - prev.cpuSeconds) / seconds * 100
+	usage.IoReadBytesPerSec = rate(readBytes, seconds)
+	usage.IoWriteBytesPerSec = rate(writeBytes, seconds)
+}
+
+// findContainers walks a cgroup hierarchy and returns the container leaf cgroups
+func findContainers(ctx context.Context, dir string) ([]containerCgroup, error) {
+	var containers []containerCgroup
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if p == dir {
+				return err
+			}
+			// Cgroups disappear while we walk the tree
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		match := containerIDPattern.FindStringSubmatch(d.Name())
+		if match == nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		container := containerCgroup{id: match[1], path: filepath.ToSlash(rel)}
+		if pod := podUIDPattern.FindStringSubmatch(rel); pod != nil {
+			container.podUID = strings.ReplaceAll(pod[1], "_", "-")
+		}
+		containers = append(containers, container)
+
+		// Nested cgroups belong to the same container
+		return filepath.SkipDir
+	})
+	return containers, err
+}
+
+// cgroupReader knows where each controller lives for the detected cgroup version
+type cgroupReader struct {
+	root    string
+	version string
+	// v1 controller hierarchies, empty when not mounted
+	cpu, cpuacct, memory, blkio string
+}
+
+func newCgroupReader(root string) *cgroupReader {
+	r := &cgroupReader{root: root, version: "v2"}
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return r
+	}
+
+	r.version = "v1"
+	r.cpu = firstDir(root, "cpu", "cpu,cpuacct")
+	r.cpuacct = firstDir(root, "cpuacct", "cpu,cpuacct", "cpuacct,cpu")
+	r.memory = firstDir(root, "memory")
+	r.blkio = firstDir(root, "blkio")
+	return r
+}
+
+func firstDir(root string, names ...string) string {
+	for _, name := range names {
+		p := filepath.Join(root, name)
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// discoveryDir is the hierarchy walked to find containers
+func (r *cgroupReader) discoveryDir() string {
+	if r.version == "v2" {
+		return r.root
+	}
+	for _, dir := range []string{r.memory, r.cpuacct, r.cpu} {
+		if dir != "" {
+			return dir
+		}
+	}
+	return filepath.Join(r.root, "memory")
+}
+
+// read collects what is available for one container, missing files leave zeros
+func (r *cgroupReader) read(container containerCgroup) *pb.ContainerUsage {
+	usage := &pb.ContainerUsage{
+		ContainerId:   container.id,
+		PodUid:        container.podUID,
+		CgroupPath:    container.path,
+		CgroupVersion: r.version,
+	}
+	if r.version == "v2" {
+		r.readV2(usage, filepath.Join(r.root, container.path))
+	} else {
+		r.readV1(usage, container.path)
+	}
+
+	if usage.MemoryWorkingSetBytes > usage.MemoryUsageBytes {
+		usage.MemoryWorkingSetBytes = 0
+	}
+	return usage
+}
+
+func (r *cgroupReader) readV2(usage *pb.ContainerUsage, dir string) {
+	cpuStat := readKeyValues(filepath.Join(dir, "cpu.stat"))
+	usage.CpuUsageSeconds = float64(cpuStat["usage_usec"]) / 1e6
+	usage.CpuPeriods = cpuStat["nr_periods"]
+	usage.CpuThrottledPeriods = cpuStat["nr_throttled"]
+	usage.CpuThrottledSeconds = float64(cpuStat["throttled_usec"]) / 1e6
+
+	// cpu.max is "<quota> <period>" or "max <period>"
+	if fields := strings.Fields(readString(filepath.Join(dir, "cpu.max"))); len(fields) == 2 {
+		usage.CpuLimitCores = cpuLimit(fields[0], fields[1])
+	}
+
+	usage.MemoryUsageBytes, _ = readUint(filepath.Join(dir, "memory.current"))
+	if limit := readString(filepath.Join(dir, "memory.max")); limit != "max" {
+		usage.MemoryLimitBytes, _ = strconv.ParseUint(limit, 10, 64)
+	}
+	memStat := readKeyValues(filepath.Join(dir, "memory.stat"))
+	usage.MemoryWorkingSetBytes = usage.MemoryUsageBytes - memStat["inactive_file"]
+	usage.OomKills = readKeyValues(filepath.Join(dir, "memory.events"))["oom_kill"]
+
+	// io.stat: "<major>:<minor> rbytes=N wbytes=N rios=N wios=N dbytes=N dios=N"
+	for _, line := range readLines(filepath.Join(dir, "io.stat")) {
+		for _, field := range strings.Fields(line)[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				usage.IoReadBytes += n
+			case "wbytes":
+				usage.IoWriteBytes += n
+			case "rios":
+				usage.IoReads += n
+			case "wios":
+				usage.IoWrites += n
+			}
+		}
+	}
+}
+
+func (r *cgroupReader) readV1(usage *pb.ContainerUsage, rel string) {
+	if r.cpuacct != "" {
+		ns, _ := readUint(filepath.Join(r.cpuacct, rel, "cpuacct.usage"))
+		usage.CpuUsageSeconds = float64(ns) / 1e9
+	}
+
+	if r.cpu != "" {
+		dir := filepath.Join(r.cpu, rel)
+		cpuStat := readKeyValues(filepath.Join(dir, "cpu.stat"))
+		usage.CpuPeriods = cpuStat["nr_periods"]
+		usage.CpuThrottledPeriods = cpuStat["nr_throttled"]
+		usage.CpuThrottledSeconds = float64(cpuStat["throttled_time"]) / 1e9
+		usage.CpuLimitCores = cpuLimit(
+			readString(filepath.Join(dir, "cpu.cfs_quota_us")),
+			readString(filepath.Join(dir, "cpu.cfs_period_us")),
+		)
+	}
+
+	if r.memory != "" {
+		dir := filepath.Join(r.memory, rel)
+		usage.MemoryUsageBytes, _ = readUint(filepath.Join(dir, "memory.usage_in_bytes"))
+		if limit, err := readUint(filepath.Join(dir, "memory.limit_in_bytes")); err == nil && limit < cgroupV1Unlimited {
+			usage.MemoryLimitBytes = limit
+		}
+		memStat := readKeyValues(filepath.Join(dir, "memory.stat"))
+		usage.MemoryWorkingSetBytes = usage.MemoryUsageBytes - memStat["total_inactive_file"]
+		usage.OomKills = readKeyValues(filepath.Join(dir, "memory.oom_control"))["oom_kill"]
+	}
+
+	if r.blkio != "" {
+		dir := filepath.Join(r.blkio, rel)
+		usage.IoReadBytes, usage.IoWriteBytes = readBlkioTotals(filepath.Join(dir, "blkio.throttle.io_service_bytes"))
+		usage.IoReads, usage.IoWrites = readBlkioTotals(filepath.Join(dir, "blkio.throttle.io_serviced"))
+	}
+}
+
+// cpuLimit converts a CFS quota and period to cores, 0 meaning unlimited
+func cpuLimit(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// readBlkioTotals sums the per-device "<major>:<minor> Read|Write N" lines
+func readBlkioTotals(file string) (read, write uint64) {
+	for _, line := range readLines(file) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		n, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			read += n
+		case "Write":
+			write += n
+		}
+	}
+	return read, write
+}
+
+func readString(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readUint(file string) (uint64, error) {
+	s := readString(file)
+	if s == "" {
+		return 0, errors.New("empty or missing " + file)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func readLines(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// readKeyValues parses flat keyed files such as cpu.stat and memory.events
+func readKeyValues(file string) map[string]uint64 {
+	values := make(map[string]uint64)
+	for _, line := range readLines(file) {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values
+}
@@ -0,0 +1,152 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "gomon/pb"
+)
+
+const (
+	testContainerID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testPodUID      = "8a3d1c2e-4b5f-4c6d-9e7f-0a1b2c3d4e5f"
+)
+
+func writeFixture(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCgroupV2(t *testing.T) {
+	root := t.TempDir()
+	pod := "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + strings.ReplaceAll(testPodUID, "-", "_") + ".slice"
+	container := pod + "/cri-containerd-" + testContainerID + ".scope"
+	writeFixture(t, root, map[string]string{
+		"cgroup.controllers":                 "cpu io memory",
+		pod + "/cpu.stat":                    "usage_usec 99999999\n",
+		container + "/cpu.stat":              "usage_usec 2500000\nnr_periods 100\nnr_throttled 25\nthrottled_usec 500000\n",
+		container + "/cpu.max":               "50000 100000\n",
+		container + "/memory.current":        "104857600\n",
+		container + "/memory.max":            "max\n",
+		container + "/memory.stat":           "anon 1\ninactive_file 4857600\n",
+		container + "/memory.events":         "low 0\nhigh 0\nmax 3\noom 2\noom_kill 1\n",
+		container + "/io.stat":               "8:0 rbytes=1000 wbytes=2000 rios=10 wios=20 dbytes=0 dios=0\n8:16 rbytes=24 wbytes=48 rios=1 wios=2\n",
+		container + "/nested/cgroup.procs":   "1\n",
+		"system.slice/sshd.service/cpu.stat": "usage_usec 1\n",
+	})
+
+	reader := newCgroupReader(root)
+	containers, err := findContainers(context.Background(), reader.discoveryDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("Expected one container, got %+v", containers)
+	}
+	if containers[0].id != testContainerID || containers[0].podUID != testPodUID {
+		t.Errorf("Unexpected container identity: %+v", containers[0])
+	}
+
+	usage := reader.read(containers[0])
+	if usage.CgroupVersion != "v2" || usage.CpuUsageSeconds != 2.5 {
+		t.Errorf("Unexpected CPU usage: %+v", usage)
+	}
+	if usage.CpuLimitCores != 0.5 || usage.CpuThrottledPeriods != 25 || usage.CpuThrottledSeconds != 0.5 {
+		t.Errorf("Unexpected CPU limits: %+v", usage)
+	}
+	if usage.MemoryUsageBytes != 104857600 || usage.MemoryWorkingSetBytes != 100000000 || usage.MemoryLimitBytes != 0 {
+		t.Errorf("Unexpected memory: %+v", usage)
+	}
+	if usage.OomKills != 1 {
+		t.Errorf("Expected 1 OOM kill, got %d", usage.OomKills)
+	}
+	if usage.IoReadBytes != 1024 || usage.IoWriteBytes != 2048 || usage.IoReads != 11 || usage.IoWrites != 22 {
+		t.Errorf("Unexpected I/O: %+v", usage)
+	}
+}
+
+func TestCgroupV1(t *testing.T) {
+	root := t.TempDir()
+	rel := "kubepods/burstable/pod" + testPodUID + "/" + testContainerID
+	writeFixture(t, root, map[string]string{
+		"cpu,cpuacct/" + rel + "/cpuacct.usage":                    "3000000000\n",
+		"cpu,cpuacct/" + rel + "/cpu.stat":                         "nr_periods 10\nnr_throttled 2\nthrottled_time 1500000000\n",
+		"cpu,cpuacct/" + rel + "/cpu.cfs_quota_us":                 "-1\n",
+		"cpu,cpuacct/" + rel + "/cpu.cfs_period_us":                "100000\n",
+		"memory/" + rel + "/memory.usage_in_bytes":                 "2048\n",
+		"memory/" + rel + "/memory.limit_in_bytes":                 "9223372036854771712\n",
+		"memory/" + rel + "/memory.stat":                           "cache 10\ntotal_inactive_file 4096\n",
+		"memory/" + rel + "/memory.oom_control":                    "oom_kill_disable 0\nunder_oom 0\noom_kill 4\n",
+		"blkio/" + rel + "/blkio.throttle.io_service_bytes":        "8:0 Read 512\n8:0 Write 256\n8:0 Total 768\nTotal 768\n",
+		"blkio/" + rel + "/blkio.throttle.io_serviced":             "8:0 Read 5\n8:0 Write 3\nTotal 8\n",
+		"memory/system.slice/docker.service/memory.usage_in_bytes": "1\n",
+	})
+
+	reader := newCgroupReader(root)
+	containers, err := findContainers(context.Background(), reader.discoveryDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 1 || containers[0].podUID != testPodUID {
+		t.Fatalf("Unexpected containers: %+v", containers)
+	}
+
+	usage := reader.read(containers[0])
+	if usage.CgroupVersion != "v1" || usage.CpuUsageSeconds != 3 || usage.CpuThrottledSeconds != 1.5 {
+		t.Errorf("Unexpected CPU: %+v", usage)
+	}
+	if usage.CpuLimitCores != 0 || usage.MemoryLimitBytes != 0 {
+		t.Errorf("Unlimited cgroup must report zero limits: %+v", usage)
+	}
+	// Inactive file cache larger than usage must not wrap around
+	if usage.MemoryUsageBytes != 2048 || usage.MemoryWorkingSetBytes != 0 {
+		t.Errorf("Unexpected memory: %+v", usage)
+	}
+	if usage.OomKills != 4 || usage.IoReadBytes != 512 || usage.IoWriteBytes != 256 || usage.IoReads != 5 || usage.IoWrites != 3 {
+		t.Errorf("Unexpected OOM/I/O: %+v", usage)
+	}
+}
+
+func TestCgroupRates(t *testing.T) {
+	root := t.TempDir()
+	container := "system.slice/docker-" + testContainerID + ".scope"
+	writeFixture(t, root, map[string]string{
+		"cgroup.controllers":    "cpu io memory",
+		container + "/cpu.stat": "usage_usec 1000000\n",
+		container + "/io.stat":  "8:0 rbytes=0 wbytes=0\n",
+	})
+
+	c := &cgroupCollector{root: root}
+	reader := newCgroupReader(root)
+	containers, _ := findContainers(context.Background(), reader.discoveryDir())
+	start := time.Unix(1700000000, 0)
+
+	first := c.update([]*pb.ContainerUsage{reader.read(containers[0])}, start)
+	if first[0].RateIntervalSeconds != 0 || first[0].PodUid != "" {
+		t.Fatalf("First cycle must not report rates: %+v", first[0])
+	}
+
+	writeFixture(t, root, map[string]string{
+		container + "/cpu.stat": "usage_usec 16000000\n", // 1.5 cores over 10s
+		container + "/io.stat":  "8:0 rbytes=10240 wbytes=20480\n",
+	})
+	second := c.update([]*pb.ContainerUsage{reader.read(containers[0])}, start.Add(10*time.Second))
+	if second[0].CpuUsagePercent != 150 {
+		t.Errorf("Expected 150%% CPU, got %v", second[0].CpuUsagePercent)
+	}
+	if second[0].IoReadBytesPerSec != 1024 || second[0].IoWriteBytesPerSec != 2048 {
+		t.Errorf("Unexpected I/O rates: %+v", second[0])
+	}
+}
@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shirou/gopsutil/v3/common"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+var tracer = otel.Tracer("gomon/agent/collector")
+
+// Collector gathers one family of host metrics
+type Collector interface {
+	Name() string
+	// Collect returns a partial Metric holding only the fields this collector owns
+	Collect(ctx context.Context) (*pb.Metric, error)
+}
+
+// Factory builds a collector from the agent configuration
+type Factory func(cfg config.Config) Collector
+
+var factories = make(map[string]Factory)
+
+// Register makes a collector available to NewRegistry, it is meant to be called from init()
+func Register(name string, factory Factory) {
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("collector %q registered twice", name))
+	}
+	factories[name] = factory
+}
+
+// Names returns all registered collector names in stable order
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	collectErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_agent_collector_errors_total",
+			Help: "Total number of failed or timed out collections per collector",
+		},
+		[]string{"collector"},
+	)
+	collectDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "gomon_agent_collector_duration_seconds",
+			Help: "Time taken by a single collection per collector",
+		},
+		[]string{"collector"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(collectErrors)
+	prometheus.MustRegister(collectDuration)
+}
+
+type entry struct {
+	collector Collector
+	timeout   time.Duration
+}
+
+// Registry runs the enabled collectors of one agent
+type Registry struct {
+	entries []entry
+	// Host filesystem locations handed to gopsutil through the context
+	env common.EnvMap
+}
+
+// NewRegistry instantiates every registered collector enabled in cfg
+func NewRegistry(cfg config.Config) (*Registry, error) {
+	r := &Registry{env: hostEnv(cfg.Host)}
+	for _, name := range Names() {
+		settings, ok := cfg.Collectors.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("collector %q has no configuration section", name)
+		}
+		if !settings.Enabled {
+			continue
+		}
+		r.entries = append(r.entries, entry{
+			collector: factories[name](cfg),
+			timeout:   settings.Timeout,
+		})
+	}
+	if len(r.entries) == 0 {
+		return nil, errors.New("no collectors enabled")
+	}
+	return r, nil
+}
+
+// Enabled returns the names of the collectors this registry runs
+func (r *Registry) Enabled() []string {
+	names := make([]string, 0, len(r.entries))
+	for _, e := range r.entries {
+		names = append(names, e.collector.Name())
+	}
+	return names
+}
+
+// Collect runs all collectors concurrently, each under its own timeout and span,
+// and merges their results into metric. Failed collectors are counted and skipped.
+func (r *Registry) Collect(ctx context.Context, metric *pb.Metric) {
+	if r.env != nil {
+		ctx = context.WithValue(ctx, common.EnvKey, r.env)
+	}
+	results := make([]*pb.Metric, len(r.entries))
+
+	var wg sync.WaitGroup
+	for i, e := range r.entries {
+		wg.Add(1)
+		go func(i int, e entry) {
+			defer wg.Done()
+			results[i] = run(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	// Merge in registry order so the payload layout is deterministic
+	for _, partial := range results {
+		if partial != nil {
+			proto.Merge(metric, partial)
+		}
+	}
+}
+
+type outcome struct {
+	metric *pb.Metric
+	err    error
+}
+
+func run(ctx context.Context, e entry) *pb.Metric {
+	name := e.collector.Name()
+
+	ctx, span := tracer.Start(ctx, "collect-"+name)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan outcome, 1)
+	go func() {
+		m, err := e.collector.Collect(ctx)
+		done <- outcome{metric: m, err: err}
+	}()
+
+	var res outcome
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		// Collector ignored the deadline, abandon it
+		res = outcome{err: ctx.Err()}
+	}
+	collectDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+	if res.err != nil {
+		log.Printf("Error collecting %s stats: %v", name, res.err)
+		collectErrors.WithLabelValues(name).Inc()
+		span.RecordError(res.err)
+		span.SetStatus(codes.Error, "collection failed")
+		return nil
+	}
+	return res.metric
+}
+
+// hostEnv maps the host configuration to the gopsutil environment
+func hostEnv(host config.HostConfig) common.EnvMap {
+	return common.EnvMap{
+		common.HostProcEnvKey: host.Proc,
+		common.HostSysEnvKey:  host.Sys,
+		common.HostRootEnvKey: host.Root,
+	}
+}
+
+func logGoroutineInfo() string {
+	buf := make([]byte, 1024)
+	// Capture the stack trace of the current goroutine
+	n := runtime.Stack(buf, false)
+	// Filter and extract only the goroutine info
+	stackTrace := string(buf[:n])
+	lines := strings.Split(stackTrace, "\n")
+
+	// Just return the first line, which includes the goroutine ID and state
+	if len(lines) > 0 {
+		return lines[0]
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeCollector struct {
+	name   string
+	metric *pb.Metric
+	err    error
+	delay  time.Duration
+}
+
+func (f *fakeCollector) Name() string { return f.name }
+
+func (f *fakeCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return f.metric, f.err
+}
+
+func TestRegistryMergesResults(t *testing.T) {
+	r := &Registry{entries: []entry{
+		{collector: &fakeCollector{name: "fake-cpu", metric: &pb.Metric{CpuUsagePercent: 12.5}}, timeout: time.Second},
+		{collector: &fakeCollector{name: "fake-net", metric: &pb.Metric{
+			NetStats: []*pb.NetworkUsage{{InterfaceName: "eth0"}},
+		}}, timeout: time.Second},
+	}}
+
+	metric := &pb.Metric{CorrelationId: "abc"}
+	r.Collect(context.Background(), metric)
+
+	if metric.CpuUsagePercent != 12.5 {
+		t.Errorf("CPU not merged: got %v", metric.CpuUsagePercent)
+	}
+	if len(metric.NetStats) != 1 || metric.NetStats[0].InterfaceName != "eth0" {
+		t.Errorf("NetStats not merged: got %v", metric.NetStats)
+	}
+	if metric.CorrelationId != "abc" {
+		t.Errorf("CorrelationId overwritten: got %q", metric.CorrelationId)
+	}
+}
+
+func TestRegistryIsolatesFailures(t *testing.T) {
+	failedBefore := testutil.ToFloat64(collectErrors.WithLabelValues("fake-broken"))
+	slowBefore := testutil.ToFloat64(collectErrors.WithLabelValues("fake-slow"))
+
+	r := &Registry{entries: []entry{
+		{collector: &fakeCollector{name: "fake-broken", err: errors.New("boom")}, timeout: time.Second},
+		{collector: &fakeCollector{name: "fake-slow", delay: time.Second, metric: &pb.Metric{MemoryTotalGb: 1}}, timeout: 10 * time.Millisecond},
+		{collector: &fakeCollector{name: "fake-ok", metric: &pb.Metric{CpuUsagePercent: 1}}, timeout: time.Second},
+	}}
+
+	metric := &pb.Metric{}
+	r.Collect(context.Background(), metric)
+
+	if metric.CpuUsagePercent != 1 {
+		t.Errorf("Healthy collector result lost: got %v", metric.CpuUsagePercent)
+	}
+	if metric.MemoryTotalGb != 0 {
+		t.Errorf("Timed out collector result should be dropped, got %v", metric.MemoryTotalGb)
+	}
+	if got := testutil.ToFloat64(collectErrors.WithLabelValues("fake-broken")) - failedBefore; got != 1 {
+		t.Errorf("Expected 1 error for fake-broken, got %v", got)
+	}
+	if got := testutil.ToFloat64(collectErrors.WithLabelValues("fake-slow")) - slowBefore; got != 1 {
+		t.Errorf("Expected 1 timeout for fake-slow, got %v", got)
+	}
+}
+
+func TestNewRegistryHonoursEnabled(t *testing.T) {
+	t.Setenv("AGENT_COLLECTORS", "memory,cpu")
+	t.Setenv("CONFIG_PATH", "../../configs/agent.yaml")
+	t.Setenv("KAFKA_BROKERS", "localhost:9092")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	r, err := NewRegistry(cfg)
+	if err != nil {
+		t.Fatalf("NewRegistry failed: %v", err)
+	}
+
+	enabled := r.Enabled()
+	if len(enabled) != 2 || enabled[0] != "cpu" || enabled[1] != "memory" {
+		t.Errorf("Unexpected enabled collectors: %v", enabled)
+	}
+}
@@ -0,0 +1,26 @@
+package collector
+
+import "math"
+
+// counterDelta returns how much a monotonic counter grew between two samples.
+// A counter that went backwards is treated as a 32-bit wrap when it was close to
+// the 32-bit limit and restarted near zero; anything else is a reset (interface
+// or device re-created) and reported as not ok, so the caller re-baselines.
+func counterDelta(prev, curr uint64) (uint64, bool) {
+	if curr >= prev {
+		return curr - prev, true
+	}
+	const wrapZone = math.MaxUint32 / 4
+	if prev <= math.MaxUint32 && prev >= math.MaxUint32-wrapZone && curr < wrapZone {
+		return (math.MaxUint32 - prev) + curr + 1, true
+	}
+	return 0, false
+}
+
+// rate turns a counter delta into a per-second value
+func rate(delta uint64, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(delta) / seconds
+}
@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("cpu", func(cfg config.Config) Collector {
+		return &cpuCollector{sampleWindow: cfg.Collectors.CPU.SampleWindow}
+	})
+}
+
+// cpuCollector derives utilisation from CPU time deltas against the previous
+// cycle. Only the very first collection waits sampleWindow to get a baseline.
+type cpuCollector struct {
+	sampleWindow time.Duration
+
+	mu        sync.Mutex
+	prevTotal *cpu.TimesStat
+	prevCores map[string]cpu.TimesStat
+}
+
+func (c *cpuCollector) Name() string { return "cpu" }
+
+func (c *cpuCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect CPU stats...", logGoroutineInfo())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.prevTotal == nil {
+		if err := c.snapshot(ctx); err != nil {
+			return nil, err
+		}
+		select {
+		case <-time.After(c.sampleWindow):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	prevTotal, prevCores := *c.prevTotal, c.prevCores
+	if err := c.snapshot(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := &pb.CpuStats{
+		Total: cpuTimesPercent("cpu-total", prevTotal, *c.prevTotal),
+	}
+	for _, name := range sortedCores(c.prevCores) {
+		prev, ok := prevCores[name]
+		if !ok {
+			// CPU came online since the last cycle
+			continue
+		}
+		stats.Cores = append(stats.Cores, cpuTimesPercent(name, prev, c.prevCores[name]))
+	}
+
+	avg, err := load.AvgWithContext(ctx)
+	if err != nil {
+		log.Printf("Error getting load averages: %v", err)
+	} else {
+		stats.Load1, stats.Load5, stats.Load15 = avg.Load1, avg.Load5, avg.Load15
+	}
+
+	usage := stats.Total.UsagePercent
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Float64("cpu_usage_percent", usage))
+	log.Printf("%s: CPU Usage: %.2f%% (user %.2f%%, system %.2f%%, iowait %.2f%%, steal %.2f%%), Load: %.2f %.2f %.2f\n",
+		logGoroutineInfo(), usage, stats.Total.UserPercent, stats.Total.SystemPercent,
+		stats.Total.IowaitPercent, stats.Total.StealPercent, stats.Load1, stats.Load5, stats.Load15)
+
+	return &pb.Metric{
+		CpuUsagePercent: float32(usage),
+		CpuStats:        stats,
+	}, nil
+}
+
+// snapshot stores the current aggregate and per-core CPU times
+func (c *cpuCollector) snapshot(ctx context.Context) error {
+	total, err := cpu.TimesWithContext(ctx, false)
+	if err != nil {
+		return err
+	}
+	if len(total) == 0 {
+		return errors.New("no CPU times reported")
+	}
+
+	perCore, err := cpu.TimesWithContext(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	cores := make(map[string]cpu.TimesStat, len(perCore))
+	for _, core := range perCore {
+		cores[core.CPU] = core
+	}
+
+	c.prevTotal = &total[0]
+	c.prevCores = cores
+	return nil
+}
+
+// totalTime excludes guest time, which Linux already accounts in user/nice
+func totalTime(t cpu.TimesStat) float64 {
+	return t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+}
+
+func cpuTimesPercent(name string, prev, curr cpu.TimesStat) *pb.CpuTimesPercent {
+	result := &pb.CpuTimesPercent{Cpu: name}
+
+	elapsed := totalTime(curr) - totalTime(prev)
+	if elapsed <= 0 {
+		return result
+	}
+
+	percent := func(p, c float64) float64 {
+		delta := c - p
+		if delta < 0 {
+			return 0
+		}
+		return delta / elapsed * 100
+	}
+
+	result.UserPercent = percent(prev.User, curr.User)
+	result.SystemPercent = percent(prev.System, curr.System)
+	result.IdlePercent = percent(prev.Idle, curr.Idle)
+	result.NicePercent = percent(prev.Nice, curr.Nice)
+	result.IowaitPercent = percent(prev.Iowait, curr.Iowait)
+	result.IrqPercent = percent(prev.Irq, curr.Irq)
+	result.SoftirqPercent = percent(prev.Softirq, curr.Softirq)
+	result.StealPercent = percent(prev.Steal, curr.Steal)
+
+	usage := 100 - result.IdlePercent - result.IowaitPercent
+	if usage < 0 {
+		usage = 0
+	}
+	result.UsagePercent = usage
+
+	return result
+}
+
+// sortedCores orders cpu0, cpu1, ..., cpu10 numerically
+func sortedCores(cores map[string]cpu.TimesStat) []string {
+	names := make([]string, 0, len(cores))
+	for name := range cores {
+		names = append(names, name)
+	}
+	index := func(name string) int {
+		i, err := strconv.Atoi(strings.TrimPrefix(name, "cpu"))
+		if err != nil {
+			return -1
+		}
+		return i
+	}
+	sort.Slice(names, func(a, b int) bool {
+		ia, ib := index(names[a]), index(names[b])
+		if ia != ib {
+			return ia < ib
+		}
+		return names[a] < names[b]
+	})
+	return names
+}
@@ -0,0 +1,37 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+func TestCPUTimesPercent(t *testing.T) {
+	prev := cpu.TimesStat{CPU: "cpu0", User: 100, System: 50, Idle: 800, Iowait: 40, Steal: 10}
+	curr := cpu.TimesStat{CPU: "cpu0", User: 130, System: 60, Idle: 850, Iowait: 50, Steal: 10, Guest: 25}
+
+	got := cpuTimesPercent("cpu0", prev, curr)
+
+	// 100 ticks elapsed: 30 user, 10 system, 50 idle, 10 iowait
+	if got.UserPercent != 30 || got.SystemPercent != 10 || got.IdlePercent != 50 || got.IowaitPercent != 10 {
+		t.Errorf("Unexpected breakdown: %+v", got)
+	}
+	if got.UsagePercent != 40 {
+		t.Errorf("Expected usage 40%%, got %v", got.UsagePercent)
+	}
+	if got.StealPercent != 0 {
+		t.Errorf("Expected no steal, got %v", got.StealPercent)
+	}
+}
+
+func TestSortedCores(t *testing.T) {
+	cores := map[string]cpu.TimesStat{"cpu10": {}, "cpu2": {}, "cpu0": {}, "cpu1": {}}
+
+	got := sortedCores(cores)
+	want := []string{"cpu0", "cpu1", "cpu2", "cpu10"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("disk", func(cfg config.Config) Collector {
+		return &diskCollector{filter: cfg.Collectors.Disk, hostRoot: cfg.Host.Root}
+	})
+}
+
+type diskCollector struct {
+	filter config.DiskConfig
+	// Mountpoints are relative to the host root, which differs from ours in a pod
+	hostRoot string
+}
+
+func (c *diskCollector) Name() string { return "disk" }
+
+func (c *diskCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect Disk stats...", logGoroutineInfo())
+	all, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := filterPartitions(all, c.filter)
+
+	metric := &pb.Metric{}
+	var totalDiskSpaceGB uint64
+	var totalDiskUsedGB uint64
+
+	for _, partition := range partitions {
+		usage, err := disk.UsageWithContext(ctx, filepath.Join(c.hostRoot, partition.Mountpoint))
+		if err != nil {
+			log.Printf("Error fetching disk usage: %v\n", err)
+			continue
+		}
+
+		totalDiskSpaceGB += usage.Total / (1 << 30)
+		totalDiskUsedGB += usage.Used / (1 << 30)
+
+		metric.DiskStats = append(metric.DiskStats, &pb.DiskUsage{
+			Mountpoint:        partition.Mountpoint,
+			UsedPercent:       float32(usage.UsedPercent),
+			TotalGb:           usage.Total,
+			UsedGb:            usage.Used,
+			Device:            partition.Device,
+			Fstype:            partition.Fstype,
+			InodesTotal:       usage.InodesTotal,
+			InodesUsed:        usage.InodesUsed,
+			InodesFree:        usage.InodesFree,
+			InodesUsedPercent: usage.InodesUsedPercent,
+		})
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("partitions_found", len(all)),
+		attribute.Int("partitions_processed", len(metric.DiskStats)),
+		attribute.Int64("total_disk_space_gb", int64(totalDiskSpaceGB)),
+		attribute.Int64("total_disk_used_gb", int64(totalDiskUsedGB)),
+	)
+	if totalDiskSpaceGB > 0 {
+		diskUsagePercent := float64(totalDiskUsedGB) / float64(totalDiskSpaceGB) * 100
+		span.SetAttributes(attribute.Float64("total_disk_used_percent", diskUsagePercent))
+	}
+
+	return metric, nil
+}
+
+// filterPartitions applies the mountpoint/fstype include and exclude rules and
+// keeps a single mount per block device, preferring the shortest mountpoint.
+func filterPartitions(partitions []disk.PartitionStat, filter config.DiskConfig) []disk.PartitionStat {
+	kept := make([]disk.PartitionStat, 0, len(partitions))
+	byDevice := make(map[string]int)
+
+	for _, p := range partitions {
+		if len(filter.IncludeFstypes) > 0 && !containsString(filter.IncludeFstypes, p.Fstype) {
+			continue
+		}
+		if containsString(filter.ExcludeFstypes, p.Fstype) {
+			continue
+		}
+		if len(filter.IncludeMountpoints) > 0 && !matchAnyMount(filter.IncludeMountpoints, p.Mountpoint) {
+			continue
+		}
+		if matchAnyMount(filter.ExcludeMountpoints, p.Mountpoint) {
+			continue
+		}
+
+		// Only real block devices are deduplicated, "tmpfs" or "none" are not unique names
+		if strings.HasPrefix(p.Device, "/") {
+			if i, seen := byDevice[p.Device]; seen {
+				if len(p.Mountpoint) < len(kept[i].Mountpoint) {
+					kept[i] = p
+				}
+				continue
+			}
+			byDevice[p.Device] = len(kept)
+		}
+		kept = append(kept, p)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Mountpoint < kept[j].Mountpoint })
+	return kept
+}
+
+// matchAnyMount matches path globs, a trailing "/**" also matches the whole subtree
+func matchAnyMount(patterns []string, mountpoint string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if mountpoint == prefix || strings.HasPrefix(mountpoint, prefix+"/") {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, mountpoint); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"testing"
+
+	"gomon/agent/internal/config"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func TestFilterPartitions(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		{Device: "/dev/sda1", Mountpoint: "/etc/hosts", Fstype: "ext4"},
+		{Device: "/dev/sda1", Mountpoint: "/var/lib/kubelet", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+		{Device: "overlay", Mountpoint: "/var/lib/containerd/rootfs/abc", Fstype: "overlay"},
+		{Device: "tmpfs", Mountpoint: "/run/secrets", Fstype: "tmpfs"},
+		{Device: "/dev/loop0", Mountpoint: "/snap/core/1", Fstype: "squashfs"},
+		{Device: "nsfs", Mountpoint: "/run/netns/cni-1", Fstype: "nsfs"},
+	}
+
+	got := filterPartitions(partitions, config.Default().Collectors.Disk)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d: %+v", len(got), got)
+	}
+	if got[0].Mountpoint != "/" || got[1].Mountpoint != "/data" {
+		t.Errorf("Unexpected mountpoints: %s, %s", got[0].Mountpoint, got[1].Mountpoint)
+	}
+}
+
+func TestFilterPartitionsInclude(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "xfs"},
+		{Device: "/dev/sdc1", Mountpoint: "/data/archive", Fstype: "xfs"},
+	}
+
+	filter := config.DiskConfig{
+		IncludeMountpoints: []string{"/data/**"},
+		IncludeFstypes:     []string{"xfs"},
+	}
+
+	got := filterPartitions(partitions, filter)
+	if len(got) != 2 || got[0].Mountpoint != "/data" || got[1].Mountpoint != "/data/archive" {
+		t.Errorf("Unexpected partitions: %+v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("diskio", func(cfg config.Config) Collector {
+		return &diskIOCollector{excludeDevices: cfg.Collectors.DiskIO.ExcludeDevices}
+	})
+}
+
+type diskIOSnapshot struct {
+	counters disk.IOCountersStat
+	taken    time.Time
+}
+
+// diskIOCollector reports per-device throughput, IOPS, latency and utilisation
+// computed against the counters kept from the previous cycle.
+type diskIOCollector struct {
+	excludeDevices []string
+
+	mu   sync.Mutex
+	prev map[string]diskIOSnapshot
+}
+
+func (c *diskIOCollector) Name() string { return "diskio" }
+
+func (c *diskIOCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect Disk I/O stats...", logGoroutineInfo())
+	counters, err := disk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]disk.IOCountersStat, 0, len(counters))
+	for _, stat := range counters {
+		if !c.excluded(stat.Name) {
+			devices = append(devices, stat)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+
+	metric := &pb.Metric{DiskIoStats: c.update(devices, time.Now())}
+
+	for _, stats := range metric.DiskIoStats {
+		log.Printf("%s: Device: %s, Read: %.2f B/s, Write: %.2f B/s, Await: %.2fms, Util: %.2f%%\n",
+			logGoroutineInfo(), stats.Device, stats.ReadBytesPerSec, stats.WriteBytesPerSec,
+			stats.AvgAwaitMs, stats.UtilizationPercent)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("devices_processed", len(metric.DiskIoStats)))
+
+	return metric, nil
+}
+
+func (c *diskIOCollector) excluded(device string) bool {
+	for _, pattern := range c.excludeDevices {
+		if ok, _ := path.Match(pattern, device); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// update records the new counters and returns stats with rates relative to the
+// previous snapshot. New or reset devices only report raw counters this cycle.
+func (c *diskIOCollector) update(counters []disk.IOCountersStat, now time.Time) []*pb.DiskIOStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := make(map[string]diskIOSnapshot, len(counters))
+	result := make([]*pb.DiskIOStats, 0, len(counters))
+
+	for _, curr := range counters {
+		stats := &pb.DiskIOStats{
+			Device:       curr.Name,
+			ReadBytes:    curr.ReadBytes,
+			WriteBytes:   curr.WriteBytes,
+			ReadCount:    curr.ReadCount,
+			WriteCount:   curr.WriteCount,
+			IoInProgress: curr.IopsInProgress,
+		}
+		next[curr.Name] = diskIOSnapshot{counters: curr, taken: now}
+
+		if prev, ok := c.prev[curr.Name]; ok {
+			applyDiskIORates(stats, prev.counters, curr, now.Sub(prev.taken).Seconds())
+		}
+		result = append(result, stats)
+	}
+
+	c.prev = next
+	return result
+}
+
+func applyDiskIORates(stats *pb.DiskIOStats, prev, curr disk.IOCountersStat, seconds float64) {
+	if seconds <= 0 {
+		return
+	}
+
+	readBytes, ok1 := counterDelta(prev.ReadBytes, curr.ReadBytes)
+	writeBytes, ok2 := counterDelta(prev.WriteBytes, curr.WriteBytes)
+	reads, ok3 := counterDelta(prev.ReadCount, curr.ReadCount)
+	writes, ok4 := counterDelta(prev.WriteCount, curr.WriteCount)
+	readTime, ok5 := counterDelta(prev.ReadTime, curr.ReadTime)
+	writeTime, ok6 := counterDelta(prev.WriteTime, curr.WriteTime)
+	ioTime, ok7 := counterDelta(prev.IoTime, curr.IoTime)
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7) {
+		// Device was re-attached, rates resume next cycle
+		return
+	}
+
+	stats.RateIntervalSeconds = seconds
+	stats.ReadBytesPerSec = rate(readBytes, seconds)
+	stats.WriteBytesPerSec = rate(writeBytes, seconds)
+	stats.ReadIops = rate(reads, seconds)
+	stats.WriteIops = rate(writes, seconds)
+
+	// Read/write times are in milliseconds summed over completed requests
+	if reads > 0 {
+		stats.ReadAwaitMs = float64(readTime) / float64(reads)
+	}
+	if writes > 0 {
+		stats.WriteAwaitMs = float64(writeTime) / float64(writes)
+	}
+	if reads+writes > 0 {
+		stats.AvgAwaitMs = float64(readTime+writeTime) / float64(reads+writes)
+	}
+
+	// IoTime is milliseconds the device had requests in flight
+	util := float64(ioTime) / (seconds * 1000) * 100
+	if util > 100 {
+		util = 100
+	}
+	stats.UtilizationPercent = util
+}
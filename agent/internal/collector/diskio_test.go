@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func TestDiskIORates(t *testing.T) {
+	c := &diskIOCollector{excludeDevices: []string{"loop*"}}
+	start := time.Unix(1700000000, 0)
+
+	if !c.excluded("loop3") || c.excluded("sda") {
+		t.Fatal("exclude_devices globs not applied")
+	}
+
+	first := c.update([]disk.IOCountersStat{{Name: "sda", ReadBytes: 4096, ReadCount: 1}}, start)
+	if first[0].RateIntervalSeconds != 0 {
+		t.Fatalf("First cycle must not report rates: %+v", first[0])
+	}
+
+	second := c.update([]disk.IOCountersStat{{
+		Name:       "sda",
+		ReadBytes:  4096 + 10*1024*1024,
+		WriteBytes: 5 * 1024 * 1024,
+		ReadCount:  1 + 200,
+		WriteCount: 100,
+		ReadTime:   400, // 2ms per read
+		WriteTime:  800, // 8ms per write
+		IoTime:     5000,
+	}}, start.Add(10*time.Second))
+
+	sda := second[0]
+	if sda.ReadBytesPerSec != 1024*1024 || sda.WriteBytesPerSec != 512*1024 {
+		t.Errorf("Unexpected throughput: read %v, write %v", sda.ReadBytesPerSec, sda.WriteBytesPerSec)
+	}
+	if sda.ReadIops != 20 || sda.WriteIops != 10 {
+		t.Errorf("Unexpected IOPS: read %v, write %v", sda.ReadIops, sda.WriteIops)
+	}
+	if sda.ReadAwaitMs != 2 || sda.WriteAwaitMs != 8 || sda.AvgAwaitMs != 4 {
+		t.Errorf("Unexpected await: read %v, write %v, avg %v", sda.ReadAwaitMs, sda.WriteAwaitMs, sda.AvgAwaitMs)
+	}
+	if sda.UtilizationPercent != 50 {
+		t.Errorf("Expected 50%% utilisation, got %v", sda.UtilizationPercent)
+	}
+}
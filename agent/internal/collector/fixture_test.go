@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+)
+
+// fixtureConfig points every collector at testdata/host instead of the live machine
+func fixtureConfig() config.Config {
+	cfg := config.Default()
+	cfg.Host = config.HostConfig{
+		Proc: "testdata/host/proc",
+		Sys:  "testdata/host/sys",
+		Root: "testdata/host/root",
+	}
+	cfg.Collectors.CPU.SampleWindow = 10 * time.Millisecond
+	return cfg
+}
+
+func TestCollectFromFixtureTree(t *testing.T) {
+	r, err := NewRegistry(fixtureConfig())
+	if err != nil {
+		t.Fatalf("NewRegistry failed: %v", err)
+	}
+
+	metric := &pb.Metric{}
+	r.Collect(context.Background(), metric)
+
+	t.Run("cpu", func(t *testing.T) {
+		cpu := metric.GetCpuStats()
+		if len(cpu.GetCores()) != 2 || cpu.Cores[1].Cpu != "cpu1" {
+			t.Errorf("Expected cpu0 and cpu1 from fixture, got %v", cpu.GetCores())
+		}
+		if cpu.GetLoad1() != 0.5 || cpu.GetLoad15() != 1 {
+			t.Errorf("Unexpected load averages: %v %v %v", cpu.GetLoad1(), cpu.GetLoad5(), cpu.GetLoad15())
+		}
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		mem := metric.GetMemoryStats()
+		if mem.GetTotalBytes() != 16384000*1024 || mem.GetAvailableBytes() != 8192000*1024 {
+			t.Errorf("Unexpected memory: %+v", mem)
+		}
+		if mem.GetCommittedBytes() != 20480000*1024 {
+			t.Errorf("Unexpected committed bytes: %d", mem.GetCommittedBytes())
+		}
+		if mem.GetSwapUsedBytes() != 1024000*1024 || mem.GetSwapUsedPercent() != 25 {
+			t.Errorf("Swap must come from the fixture meminfo, got %+v", mem)
+		}
+	})
+
+	t.Run("disk", func(t *testing.T) {
+		var mounts []string
+		for _, disk := range metric.GetDiskStats() {
+			mounts = append(mounts, disk.Mountpoint)
+		}
+		if len(mounts) != 2 || mounts[0] != "/" || mounts[1] != "/data" {
+			t.Errorf("Expected / and /data from fixture mountinfo, got %v", mounts)
+		}
+	})
+
+	t.Run("diskio", func(t *testing.T) {
+		io := metric.GetDiskIoStats()
+		if len(io) != 2 || io[0].Device != "sda" {
+			t.Fatalf("Expected sda and sda1 (loop excluded), got %v", io)
+		}
+		if io[0].ReadBytes != 4096*512 || io[0].WriteCount != 100 {
+			t.Errorf("Unexpected sda counters: %+v", io[0])
+		}
+	})
+
+	t.Run("network", func(t *testing.T) {
+		var eth0 *pb.NetworkUsage
+		for _, net := range metric.GetNetStats() {
+			if net.InterfaceName == "eth0" {
+				eth0 = net
+			}
+		}
+		if eth0 == nil {
+			t.Fatalf("Expected eth0 from fixture, got %v", metric.GetNetStats())
+		}
+		if eth0.BytesReceived != 2097152 || eth0.BytesSent != 1048576 || eth0.DropsIn != 2 {
+			t.Errorf("Unexpected eth0 counters: %+v", eth0)
+		}
+	})
+
+	t.Run("cgroup", func(t *testing.T) {
+		containers := metric.GetContainerStats()
+		if len(containers) != 1 {
+			t.Fatalf("Expected one container, got %v", containers)
+		}
+		c := containers[0]
+		if c.PodUid != "5b2d7a1c-3e4f-4a6b-8c9d-0e1f2a3b4c5d" || c.CpuUsageSeconds != 42 || c.CpuLimitCores != 2 {
+			t.Errorf("Unexpected container: %+v", c)
+		}
+		if c.MemoryWorkingSetBytes != 200000000 || c.MemoryLimitBytes != 512<<20 {
+			t.Errorf("Unexpected container memory: %+v", c)
+		}
+	})
+
+	t.Run("psi", func(t *testing.T) {
+		if len(metric.GetPressureStats()) != 6 {
+			t.Errorf("Expected some and full for cpu, memory and io, got %v", metric.GetPressureStats())
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+)
+
+// ResolveIdentity returns the hostname and identity the agent stamps on every payload.
+// Configured values win, the rest is read from the host root and sysfs so a
+// containerised agent reports the node rather than its pod.
+func ResolveIdentity(cfg config.Config) (string, *pb.HostIdentity) {
+	id := &pb.HostIdentity{
+		MachineId:       machineID(cfg.Host.Root),
+		NodeName:        cfg.Identity.NodeName,
+		CloudProvider:   cfg.Identity.CloudProvider,
+		CloudInstanceId: cfg.Identity.CloudInstanceID,
+	}
+
+	if id.CloudProvider == "" || id.CloudInstanceId == "" {
+		provider, instanceID := cloudFromDMI(filepath.Join(cfg.Host.Sys, "class", "dmi", "id"))
+		if id.CloudProvider == "" {
+			id.CloudProvider = provider
+		}
+		if id.CloudInstanceId == "" && id.CloudProvider == provider {
+			id.CloudInstanceId = instanceID
+		}
+	}
+
+	return hostname(cfg), id
+}
+
+func hostname(cfg config.Config) string {
+	if cfg.Identity.Hostname != "" {
+		return cfg.Identity.Hostname
+	}
+	// The host's /etc/hostname, os.Hostname would be the pod name
+	if name := readString(filepath.Join(cfg.Host.Root, "etc", "hostname")); name != "" {
+		return name
+	}
+	if name, err := os.Hostname(); err == nil {
+		return name
+	}
+	return ""
+}
+
+func machineID(root string) string {
+	for _, file := range []string{"etc/machine-id", "var/lib/dbus/machine-id"} {
+		if id := readString(filepath.Join(root, file)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// cloudFromDMI recognises the major clouds from the firmware tables. GCP only
+// exposes the instance id through its metadata server, so it stays empty there.
+func cloudFromDMI(dmiDir string) (provider, instanceID string) {
+	vendor := readString(filepath.Join(dmiDir, "sys_vendor"))
+	product := readString(filepath.Join(dmiDir, "product_name"))
+
+	switch {
+	case strings.HasPrefix(vendor, "Amazon EC2"):
+		// Nitro instances carry the instance id as board asset tag
+		return "aws", readString(filepath.Join(dmiDir, "board_asset_tag"))
+	case strings.HasPrefix(product, "Google Compute Engine"):
+		return "gcp", ""
+	case vendor == "Microsoft Corporation" && product == "Virtual Machine" &&
+		readString(filepath.Join(dmiDir, "chassis_asset_tag")) == azureAssetTag:
+		// product_uuid is the VM id, readable by root only
+		return "azure", readString(filepath.Join(dmiDir, "product_uuid"))
+	}
+	return "", ""
+}
+
+// Chassis asset tag set on every Azure VM
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
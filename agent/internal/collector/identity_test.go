@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveIdentityFromFixtureTree(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.Identity.NodeName = "worker-1"
+
+	hostname, id := ResolveIdentity(cfg)
+	if hostname != "node-a.example.internal" {
+		t.Errorf("Expected hostname from the host root, got %q", hostname)
+	}
+	if id.GetMachineId() != "4c4c4544003957108052b4c04f384d32" || id.GetNodeName() != "worker-1" {
+		t.Errorf("Unexpected machine id or node: %+v", id)
+	}
+	if id.GetCloudProvider() != "aws" || id.GetCloudInstanceId() != "i-0123456789abcdef0" {
+		t.Errorf("Expected the EC2 instance from DMI, got %+v", id)
+	}
+}
+
+func TestResolveIdentityOverrides(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.Identity.Hostname = "configured"
+	cfg.Identity.CloudProvider = "hetzner"
+
+	hostname, id := ResolveIdentity(cfg)
+	if hostname != "configured" {
+		t.Errorf("Expected configured hostname, got %q", hostname)
+	}
+	// The DMI instance id belongs to another provider and must not leak in
+	if id.GetCloudProvider() != "hetzner" || id.GetCloudInstanceId() != "" {
+		t.Errorf("Unexpected cloud identity: %+v", id)
+	}
+}
+
+func TestResolveIdentityWithoutHostFiles(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.Host.Root = t.TempDir()
+	cfg.Host.Sys = t.TempDir()
+
+	want, _ := os.Hostname()
+	hostname, id := ResolveIdentity(cfg)
+	if hostname != want {
+		t.Errorf("Expected os.Hostname fallback %q, got %q", want, hostname)
+	}
+	if id.GetMachineId() != "" || id.GetCloudProvider() != "" {
+		t.Errorf("Expected empty identity, got %+v", id)
+	}
+
+	dbus := filepath.Join(cfg.Host.Root, "var", "lib", "dbus")
+	if err := os.MkdirAll(dbus, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dbus, "machine-id"), []byte("abc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, id := ResolveIdentity(cfg); id.GetMachineId() != "abc" {
+		t.Errorf("Expected dbus machine id, got %q", id.GetMachineId())
+	}
+}
@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+	"gomon/schema"
+)
+
+// ResolveLabels returns the host labels the agent attaches to every payload:
+// OS and kernel, cloud region and zone, downward API labels and finally the
+// static labels from config. The labels are always usable, the error reports
+// the discovery steps that failed.
+func ResolveLabels(ctx context.Context, cfg config.Config, id *pb.HostIdentity) (map[string]string, error) {
+	labels := make(map[string]string)
+	var errs []error
+
+	if cfg.Labels.OS {
+		for name, value := range osLabels(cfg.Host) {
+			labels[name] = value
+		}
+	}
+
+	if cfg.Labels.CloudMetadata.Enabled && id.GetCloudProvider() != "" {
+		cloud, err := cloudLabels(ctx, cfg.Labels.CloudMetadata, id.CloudProvider)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cloud metadata: %w", err))
+		}
+		for name, value := range cloud {
+			labels[name] = value
+		}
+	}
+
+	keep := make(map[string]bool)
+	for _, key := range cfg.Labels.DownwardAPIKeys {
+		keep[strings.TrimSpace(key)] = true
+	}
+	for _, file := range cfg.Labels.DownwardAPIFiles {
+		values, err := readDownwardAPIFile(strings.TrimSpace(file))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for key, value := range values {
+			if len(keep) > 0 && !keep[key] {
+				continue
+			}
+			name := schema.SanitizeLabelName(key)
+			if schema.ValidateHostLabel(name) != nil {
+				continue
+			}
+			labels[name] = value
+		}
+	}
+
+	for name, value := range cfg.Labels.Static {
+		labels[name] = value
+	}
+
+	// Empty values would only produce series without the label
+	for name, value := range labels {
+		if value == "" {
+			delete(labels, name)
+		}
+	}
+
+	return labels, errors.Join(errs...)
+}
+
+// osLabels reads the distribution from os-release and the kernel release from proc
+func osLabels(host config.HostConfig) map[string]string {
+	labels := make(map[string]string)
+
+	for _, file := range []string{"etc/os-release", "usr/lib/os-release"} {
+		release := readOSRelease(filepath.Join(host.Root, file))
+		if release == nil {
+			continue
+		}
+		labels["os"] = release["ID"]
+		labels["os_version"] = release["VERSION_ID"]
+		break
+	}
+
+	labels["kernel"] = readString(filepath.Join(host.Proc, "sys", "kernel", "osrelease"))
+	return labels
+}
+
+// readOSRelease parses os-release(5), nil when the file is missing
+func readOSRelease(file string) map[string]string {
+	if _, err := os.Stat(file); err != nil {
+		return nil
+	}
+	release := make(map[string]string)
+	for _, line := range readLines(file) {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		release[key] = value
+	}
+	return release
+}
+
+// readDownwardAPIFile parses the key="value" lines the kubelet writes for
+// metadata.labels and metadata.annotations
+func readDownwardAPIFile(file string) (map[string]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("downward API labels: %w", err)
+	}
+
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		values[key] = value
+	}
+	return values, nil
+}
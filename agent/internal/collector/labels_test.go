@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "gomon/pb"
+)
+
+func TestResolveLabels(t *testing.T) {
+	podinfo := filepath.Join(t.TempDir(), "labels")
+	body := "app=\"agent\"\ncontroller-revision-hash=\"6d9f\"\ntopology.kubernetes.io/zone=\"eu-west-1a\"\n"
+	if err := os.WriteFile(podinfo, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fixtureConfig()
+	cfg.Labels.CloudMetadata.Enabled = false
+	cfg.Labels.DownwardAPIFiles = []string{podinfo}
+	cfg.Labels.DownwardAPIKeys = []string{"app", "topology.kubernetes.io/zone"}
+	cfg.Labels.Static = map[string]string{"env": "prod", "app": "gomon", "kernel": ""}
+
+	labels, err := ResolveLabels(context.Background(), cfg, &pb.HostIdentity{})
+	if err != nil {
+		t.Fatalf("ResolveLabels failed: %v", err)
+	}
+
+	want := map[string]string{
+		"os":                          "ubuntu",
+		"os_version":                  "24.04",
+		"topology_kubernetes_io_zone": "eu-west-1a",
+		"env":                         "prod",
+		"app":                         "gomon", // static wins over the downward API
+	}
+	if len(labels) != len(want) {
+		t.Errorf("Expected %v, got %v", want, labels)
+	}
+	for name, value := range want {
+		if labels[name] != value {
+			t.Errorf("Label %s: expected %q, got %q", name, value, labels[name])
+		}
+	}
+	// An empty static label removes the discovered one
+	if _, ok := labels["kernel"]; ok {
+		t.Errorf("Expected kernel label to be suppressed, got %q", labels["kernel"])
+	}
+}
+
+func TestResolveLabelsKernelAndMissingFile(t *testing.T) {
+	cfg := fixtureConfig()
+	cfg.Labels.CloudMetadata.Enabled = false
+	cfg.Labels.DownwardAPIFiles = []string{filepath.Join(t.TempDir(), "missing")}
+
+	labels, err := ResolveLabels(context.Background(), cfg, &pb.HostIdentity{})
+	if err == nil {
+		t.Error("Expected error for missing downward API file")
+	}
+	if labels["kernel"] != "6.8.0-1021-aws" || labels["os"] != "ubuntu" {
+		t.Errorf("Expected discovered labels despite the error, got %v", labels)
+	}
+}
+
+func TestCloudLabels(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("token-1"))
+	})
+	mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-aws-ec2-metadata-token") != "token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"region":"eu-west-1","availabilityZone":"eu-west-1a","instanceType":"m6i.large"}`))
+	})
+	mux.HandleFunc("GET /computeMetadata/v1/instance/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"zone":"projects/123/zones/europe-west1-b","machineType":"projects/123/machineTypes/e2-medium"}`))
+	})
+	mux.HandleFunc("GET /metadata/instance/compute", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"location":"westeurope","zone":"2","vmSize":"Standard_D2s_v5"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := fixtureConfig().Labels.CloudMetadata
+	cfg.Endpoint = server.URL
+
+	tests := map[string]map[string]string{
+		"aws":   {"region": "eu-west-1", "zone": "eu-west-1a", "instance_type": "m6i.large"},
+		"gcp":   {"region": "europe-west1", "zone": "europe-west1-b", "instance_type": "e2-medium"},
+		"azure": {"region": "westeurope", "zone": "westeurope-2", "instance_type": "Standard_D2s_v5"},
+	}
+	for provider, want := range tests {
+		labels, err := cloudLabels(context.Background(), cfg, provider)
+		if err != nil {
+			t.Errorf("%s: %v", provider, err)
+			continue
+		}
+		for name, value := range want {
+			if labels[name] != value {
+				t.Errorf("%s label %s: expected %q, got %q", provider, name, value, labels[name])
+			}
+		}
+	}
+
+	// Discovery failures are reported but leave the other labels in place
+	full := fixtureConfig()
+	full.Labels.CloudMetadata.Endpoint = server.URL + "/unreachable"
+	labels, err := ResolveLabels(context.Background(), full, &pb.HostIdentity{CloudProvider: "aws"})
+	if err == nil {
+		t.Error("Expected error from failing metadata service")
+	}
+	if labels["os"] != "ubuntu" {
+		t.Errorf("Expected os label despite the error, got %v", labels)
+	}
+}
@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"context"
+	"log"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("memory", func(cfg config.Config) Collector {
+		return &memoryCollector{}
+	})
+}
+
+type memoryCollector struct{}
+
+func (c *memoryCollector) Name() string { return "memory" }
+
+func (c *memoryCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect Memory stats...", logGoroutineInfo())
+	vMem, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := memoryStats(vMem, swapFromMeminfo(vMem))
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Float64("memory_used_percent", stats.UsedPercent),
+		attribute.Int64("memory_total_bytes", int64(stats.TotalBytes)),
+	)
+
+	log.Printf("%s: Memory Usage: %.2f%% (Total: %v, Used: %v, Available: %v, Free: %v, Buffers: %v, Cached: %v, Committed: %v),"+
+		"Swap Usage: SwapTotal: %v, SwapUsed: %v, SwapFree: %v\n", logGoroutineInfo(),
+		stats.UsedPercent, stats.TotalBytes, stats.UsedBytes, stats.AvailableBytes, stats.FreeBytes,
+		stats.BuffersBytes, stats.CachedBytes, stats.CommittedBytes,
+		stats.SwapTotalBytes, stats.SwapUsedBytes, stats.SwapFreeBytes)
+
+	return &pb.Metric{
+		MemoryUsedPercent: float32(stats.UsedPercent),
+		MemoryTotalGb:     stats.TotalBytes / (1 << 30),
+		MemoryUsedGb:      stats.UsedBytes / (1 << 30),
+		// Megabytes, as this field has always held
+		MemoryFreeGb: stats.FreeBytes / (1 << 20),
+		MemoryStats:  stats,
+	}, nil
+}
+
+func memoryStats(vMem *mem.VirtualMemoryStat, swap *mem.SwapMemoryStat) *pb.MemoryStats {
+	return &pb.MemoryStats{
+		TotalBytes:      vMem.Total,
+		UsedBytes:       vMem.Used,
+		FreeBytes:       vMem.Free,
+		AvailableBytes:  vMem.Available,
+		BuffersBytes:    vMem.Buffers,
+		CachedBytes:     vMem.Cached,
+		CommittedBytes:  vMem.CommittedAS,
+		UsedPercent:     vMem.UsedPercent,
+		SwapTotalBytes:  swap.Total,
+		SwapUsedBytes:   swap.Used,
+		SwapFreeBytes:   swap.Free,
+		SwapUsedPercent: swap.UsedPercent,
+	}
+}
+
+// swapFromMeminfo uses the meminfo swap fields rather than sysinfo(2), so swap
+// honours the configured host proc root like the rest of the memory stats
+func swapFromMeminfo(vMem *mem.VirtualMemoryStat) *mem.SwapMemoryStat {
+	swap := &mem.SwapMemoryStat{Total: vMem.SwapTotal, Free: vMem.SwapFree}
+	if swap.Free <= swap.Total {
+		swap.Used = swap.Total - swap.Free
+	}
+	if swap.Total > 0 {
+		swap.UsedPercent = float64(swap.Used) / float64(swap.Total) * 100
+	}
+	return swap
+}
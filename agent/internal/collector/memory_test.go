@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+func TestMemoryStats(t *testing.T) {
+	vMem := &mem.VirtualMemoryStat{
+		Total:       16 << 30,
+		Used:        6 << 30,
+		Free:        2 << 30,
+		Available:   9 << 30,
+		Buffers:     512 << 20,
+		Cached:      7 << 30,
+		CommittedAS: 20 << 30,
+		UsedPercent: 37.5,
+	}
+	swap := &mem.SwapMemoryStat{Total: 4 << 30, Used: 1 << 30, Free: 3 << 30, UsedPercent: 25}
+
+	stats := memoryStats(vMem, swap)
+
+	if stats.UsedPercent != 37.5 {
+		t.Errorf("Expected used percent 37.5, got %v", stats.UsedPercent)
+	}
+	if stats.AvailableBytes != 9<<30 || stats.CommittedBytes != 20<<30 {
+		t.Errorf("Unexpected available/committed: %+v", stats)
+	}
+	if stats.SwapUsedBytes != 1<<30 || stats.SwapFreeBytes != 3<<30 {
+		t.Errorf("Swap used must come from swap stats, got %+v", stats)
+	}
+}
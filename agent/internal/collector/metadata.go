@@ -0,0 +1,132 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"gomon/agent/internal/config"
+)
+
+// The metadata service is link-local, HTTP(S)_PROXY must not apply to it
+var metadataClient = &http.Client{Transport: &http.Transport{Proxy: nil}}
+
+// cloudLabels asks the instance metadata service of provider for the
+// region, zone and instance type of the host
+func cloudLabels(ctx context.Context, cfg config.CloudMetadataConfig, provider string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	switch provider {
+	case "aws":
+		return awsLabels(ctx, endpoint)
+	case "gcp":
+		return gcpLabels(ctx, endpoint)
+	case "azure":
+		return azureLabels(ctx, endpoint)
+	}
+	return nil, nil
+}
+
+// awsLabels reads the instance identity document through IMDSv2
+func awsLabels(ctx context.Context, endpoint string) (map[string]string, error) {
+	token, err := metadataRequest(ctx, http.MethodPut, endpoint+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := metadataRequest(ctx, http.MethodGet, endpoint+"/latest/dynamic/instance-identity/document",
+		map[string]string{"X-aws-ec2-metadata-token": token})
+	if err != nil {
+		return nil, err
+	}
+
+	var doc struct {
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		InstanceType     string `json:"instanceType"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, fmt.Errorf("could not parse instance identity document: %w", err)
+	}
+	return map[string]string{"region": doc.Region, "zone": doc.AvailabilityZone, "instance_type": doc.InstanceType}, nil
+}
+
+// gcpLabels reads zone and machine type, both returned as resource paths
+func gcpLabels(ctx context.Context, endpoint string) (map[string]string, error) {
+	body, err := metadataRequest(ctx, http.MethodGet, endpoint+"/computeMetadata/v1/instance/?recursive=true",
+		map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		return nil, err
+	}
+
+	var instance struct {
+		Zone        string `json:"zone"`        // projects/<number>/zones/europe-west1-b
+		MachineType string `json:"machineType"` // projects/<number>/machineTypes/e2-medium
+	}
+	if err := json.Unmarshal([]byte(body), &instance); err != nil {
+		return nil, fmt.Errorf("could not parse instance metadata: %w", err)
+	}
+
+	zone := path.Base(instance.Zone)
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+	return map[string]string{"region": region, "zone": zone, "instance_type": path.Base(instance.MachineType)}, nil
+}
+
+// azureLabels reads the compute section of the instance metadata service
+func azureLabels(ctx context.Context, endpoint string) (map[string]string, error) {
+	body, err := metadataRequest(ctx, http.MethodGet, endpoint+"/metadata/instance/compute?api-version=2021-02-01",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		return nil, err
+	}
+
+	var compute struct {
+		Location string `json:"location"`
+		Zone     string `json:"zone"` // "1", "2" or "3", empty outside availability zones
+		VMSize   string `json:"vmSize"`
+	}
+	if err := json.Unmarshal([]byte(body), &compute); err != nil {
+		return nil, fmt.Errorf("could not parse instance metadata: %w", err)
+	}
+
+	zone := ""
+	if compute.Zone != "" {
+		zone = compute.Location + "-" + compute.Zone
+	}
+	return map[string]string{"region": compute.Location, "zone": zone, "instance_type": compute.VMSize}, nil
+}
+
+func metadataRequest(ctx context.Context, method, url string, headers map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := metadataClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,124 @@
+package collector
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/net"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("network", func(cfg config.Config) Collector {
+		return &networkCollector{}
+	})
+}
+
+type netSnapshot struct {
+	counters net.IOCountersStat
+	taken    time.Time
+}
+
+// networkCollector reports raw per-interface counters plus rates computed
+// against the snapshot kept from the previous cycle, so it never blocks.
+type networkCollector struct {
+	mu   sync.Mutex
+	prev map[string]netSnapshot
+}
+
+func (c *networkCollector) Name() string { return "network" }
+
+func (c *networkCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect Network stats...", logGoroutineInfo())
+	counters, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	metric := &pb.Metric{NetStats: c.update(counters, time.Now())}
+
+	for _, usage := range metric.NetStats {
+		log.Printf("%s: Interface: %s, Sent: %.2f B/s, Received: %.2f B/s\n", logGoroutineInfo(),
+			usage.InterfaceName, usage.BytesSentPerSec, usage.BytesReceivedPerSec)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("interfaces_processed", len(metric.NetStats)))
+
+	return metric, nil
+}
+
+// update records the new counters and returns usage with rates relative to the
+// previous snapshot. Interfaces that are new or whose counters were reset only
+// report raw counters this cycle.
+func (c *networkCollector) update(counters []net.IOCountersStat, now time.Time) []*pb.NetworkUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := make(map[string]netSnapshot, len(counters))
+	usages := make([]*pb.NetworkUsage, 0, len(counters))
+
+	for _, curr := range counters {
+		usage := &pb.NetworkUsage{
+			InterfaceName:   curr.Name,
+			BytesSent:       curr.BytesSent,
+			BytesReceived:   curr.BytesRecv,
+			PacketsSent:     curr.PacketsSent,
+			PacketsReceived: curr.PacketsRecv,
+			ErrorsIn:        curr.Errin,
+			ErrorsOut:       curr.Errout,
+			DropsIn:         curr.Dropin,
+			DropsOut:        curr.Dropout,
+		}
+		next[curr.Name] = netSnapshot{counters: curr, taken: now}
+
+		if prev, ok := c.prev[curr.Name]; ok {
+			applyNetRates(usage, prev, curr, now.Sub(prev.taken).Seconds())
+		}
+		usages = append(usages, usage)
+	}
+
+	// Interfaces that disappeared are dropped with the old map
+	c.prev = next
+	return usages
+}
+
+func applyNetRates(usage *pb.NetworkUsage, prev netSnapshot, curr net.IOCountersStat, seconds float64) {
+	if seconds <= 0 {
+		return
+	}
+
+	pairs := []struct {
+		prev, curr uint64
+		dst        *float64
+	}{
+		{prev.counters.BytesSent, curr.BytesSent, &usage.BytesSentPerSec},
+		{prev.counters.BytesRecv, curr.BytesRecv, &usage.BytesReceivedPerSec},
+		{prev.counters.PacketsSent, curr.PacketsSent, &usage.PacketsSentPerSec},
+		{prev.counters.PacketsRecv, curr.PacketsRecv, &usage.PacketsReceivedPerSec},
+		{prev.counters.Errin, curr.Errin, &usage.ErrorsInPerSec},
+		{prev.counters.Errout, curr.Errout, &usage.ErrorsOutPerSec},
+		{prev.counters.Dropin, curr.Dropin, &usage.DropsInPerSec},
+		{prev.counters.Dropout, curr.Dropout, &usage.DropsOutPerSec},
+	}
+
+	rates := make([]float64, len(pairs))
+	for i, p := range pairs {
+		delta, ok := counterDelta(p.prev, p.curr)
+		if !ok {
+			// Interface was reset, rates resume next cycle
+			return
+		}
+		rates[i] = rate(delta, seconds)
+	}
+
+	for i, p := range pairs {
+		*p.dst = rates[i]
+	}
+	usage.RateIntervalSeconds = seconds
+}
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+func TestCounterDelta(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, curr uint64
+		want       uint64
+		wantOK     bool
+	}{
+		{"increase", 100, 250, 150, true},
+		{"unchanged", 42, 42, 0, true},
+		{"32-bit wrap", math.MaxUint32 - 9, 5, 15, true},
+		{"reset", 5_000_000, 1_000, 0, false},
+		{"64-bit counter going backwards", math.MaxUint32 + 100, 10, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := counterDelta(tt.prev, tt.curr)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("counterDelta(%d, %d) = %d, %v; want %d, %v", tt.prev, tt.curr, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNetworkRates(t *testing.T) {
+	c := &networkCollector{}
+	start := time.Unix(1700000000, 0)
+
+	first := c.update([]net.IOCountersStat{
+		{Name: "eth0", BytesSent: 1000, BytesRecv: 5000, PacketsSent: 10, PacketsRecv: 50},
+	}, start)
+	if len(first) != 1 || first[0].RateIntervalSeconds != 0 {
+		t.Fatalf("First cycle must not report rates: %+v", first)
+	}
+	if first[0].BytesSent != 1000 || first[0].BytesReceived != 5000 {
+		t.Errorf("Raw counters missing on first cycle: %+v", first[0])
+	}
+
+	second := c.update([]net.IOCountersStat{
+		{Name: "eth0", BytesSent: 3000, BytesRecv: 9000, PacketsSent: 30, PacketsRecv: 90, Dropin: 4},
+		{Name: "veth1", BytesSent: 10, BytesRecv: 10},
+	}, start.Add(20*time.Second))
+
+	eth0 := second[0]
+	if eth0.RateIntervalSeconds != 20 {
+		t.Errorf("Expected 20s rate interval, got %v", eth0.RateIntervalSeconds)
+	}
+	if eth0.BytesSentPerSec != 100 || eth0.BytesReceivedPerSec != 200 {
+		t.Errorf("Unexpected byte rates: sent %v, recv %v", eth0.BytesSentPerSec, eth0.BytesReceivedPerSec)
+	}
+	if eth0.PacketsSentPerSec != 1 || eth0.PacketsReceivedPerSec != 2 || eth0.DropsInPerSec != 0.2 {
+		t.Errorf("Unexpected packet/drop rates: %+v", eth0)
+	}
+	if second[1].RateIntervalSeconds != 0 {
+		t.Errorf("New interface must not report rates: %+v", second[1])
+	}
+
+	// eth0 re-created: counters restart from zero
+	third := c.update([]net.IOCountersStat{
+		{Name: "eth0", BytesSent: 20, BytesRecv: 40},
+	}, start.Add(40*time.Second))
+	if third[0].RateIntervalSeconds != 0 || third[0].BytesSentPerSec != 0 {
+		t.Errorf("Reset interface must not report rates: %+v", third[0])
+	}
+
+	fourth := c.update([]net.IOCountersStat{
+		{Name: "eth0", BytesSent: 220, BytesRecv: 40},
+	}, start.Add(60*time.Second))
+	if fourth[0].BytesSentPerSec != 10 {
+		t.Errorf("Rates must resume after reset, got %v", fourth[0].BytesSentPerSec)
+	}
+}
@@ -0,0 +1,211 @@
+package collector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("process", func(cfg config.Config) Collector {
+		return &processCollector{
+			topN:  cfg.Collectors.Process.TopN,
+			names: cfg.Collectors.Process.Names,
+		}
+	})
+}
+
+type procCPU struct {
+	createTime int64
+	cpuSeconds float64
+	taken      time.Time
+}
+
+// procSample is the cheap per-process data used for ranking
+type procSample struct {
+	proc       *process.Process
+	name       string
+	cpuPercent float64
+	rss        uint64
+}
+
+// processCollector reports the top N processes by CPU and by RSS. CPU is
+// measured against the previous cycle; processes seen for the first time
+// report their average since start.
+type processCollector struct {
+	topN  int
+	names []string
+
+	mu   sync.Mutex
+	prev map[int32]procCPU
+}
+
+func (c *processCollector) Name() string { return "process" }
+
+func (c *processCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect Process stats...", logGoroutineInfo())
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	next := make(map[int32]procCPU, len(procs))
+	samples := make([]procSample, 0, len(procs))
+
+	for _, p := range procs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Processes exit while we walk the list, skip them silently
+		name, err := p.NameWithContext(ctx)
+		if err != nil || !c.allowed(name) {
+			continue
+		}
+		times, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		memInfo, err := p.MemoryInfoWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		createTime, err := p.CreateTimeWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		cpuSeconds := times.User + times.System
+		curr := procCPU{createTime: createTime, cpuSeconds: cpuSeconds, taken: now}
+		next[p.Pid] = curr
+
+		samples = append(samples, procSample{
+			proc:       p,
+			name:       name,
+			cpuPercent: processCPUPercent(c.prev[p.Pid], curr),
+			rss:        memInfo.RSS,
+		})
+	}
+	c.prev = next
+
+	metric := &pb.Metric{}
+	for _, top := range selectTop(samples, c.topN) {
+		metric.ProcessStats = append(metric.ProcessStats, c.describe(ctx, top.sample, top.by))
+	}
+
+	for _, p := range metric.ProcessStats {
+		log.Printf("%s: Process: %s (pid %d, user %s), CPU: %.2f%%, RSS: %d, FDs: %d, Threads: %d, Top by: %v\n",
+			logGoroutineInfo(), p.Name, p.Pid, p.User, p.CpuPercent, p.RssBytes, p.OpenFds, p.Threads, p.TopBy)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("processes_scanned", len(samples)),
+		attribute.Int("processes_reported", len(metric.ProcessStats)),
+	)
+
+	return metric, nil
+}
+
+func (c *processCollector) allowed(name string) bool {
+	if len(c.names) == 0 {
+		return true
+	}
+	for _, pattern := range c.names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// describe fetches the expensive details only for the selected processes
+func (c *processCollector) describe(ctx context.Context, s procSample, by []string) *pb.ProcessUsage {
+	usage := &pb.ProcessUsage{
+		Pid:        s.proc.Pid,
+		Name:       s.name,
+		CpuPercent: s.cpuPercent,
+		RssBytes:   s.rss,
+		TopBy:      by,
+	}
+	if cmdline, err := s.proc.CmdlineWithContext(ctx); err == nil && cmdline != "" {
+		sum := sha256.Sum256([]byte(cmdline))
+		usage.CmdlineHash = hex.EncodeToString(sum[:8])
+	}
+	if user, err := s.proc.UsernameWithContext(ctx); err == nil {
+		usage.User = user
+	}
+	if fds, err := s.proc.NumFDsWithContext(ctx); err == nil {
+		usage.OpenFds = fds
+	}
+	if threads, err := s.proc.NumThreadsWithContext(ctx); err == nil {
+		usage.Threads = threads
+	}
+	return usage
+}
+
+// processCPUPercent returns CPU use since the previous sample of the same
+// process, or the lifetime average when there is none (new process, pid reuse)
+func processCPUPercent(prev, curr procCPU) float64 {
+	if prev.createTime == curr.createTime && !prev.taken.IsZero() {
+		elapsed := curr.taken.Sub(prev.taken).Seconds()
+		if elapsed > 0 && curr.cpuSeconds >= prev.cpuSeconds {
+			return (curr.cpuSeconds - prev.cpuSeconds) / elapsed * 100
+		}
+	}
+
+	lifetime := curr.taken.Sub(time.UnixMilli(curr.createTime)).Seconds()
+	if lifetime <= 0 {
+		return 0
+	}
+	return curr.cpuSeconds / lifetime * 100
+}
+
+type rankedProc struct {
+	sample procSample
+	by     []string
+}
+
+// selectTop returns the union of the top n samples by CPU and by RSS,
+// CPU leaders first, each tagged with the rankings it made
+func selectTop(samples []procSample, n int) []rankedProc {
+	byCPU := append([]procSample(nil), samples...)
+	sort.SliceStable(byCPU, func(i, j int) bool { return byCPU[i].cpuPercent > byCPU[j].cpuPercent })
+
+	byRSS := append([]procSample(nil), samples...)
+	sort.SliceStable(byRSS, func(i, j int) bool { return byRSS[i].rss > byRSS[j].rss })
+
+	var result []rankedProc
+	index := make(map[int32]int)
+
+	add := func(list []procSample, by string) {
+		for i := 0; i < n && i < len(list); i++ {
+			pid := list[i].proc.Pid
+			if at, ok := index[pid]; ok {
+				result[at].by = append(result[at].by, by)
+				continue
+			}
+			index[pid] = len(result)
+			result = append(result, rankedProc{sample: list[i], by: []string{by}})
+		}
+	}
+	add(byCPU, "cpu")
+	add(byRSS, "rss")
+
+	return result
+}
@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func TestSelectTop(t *testing.T) {
+	sample := func(pid int32, cpu float64, rss uint64) procSample {
+		return procSample{proc: &process.Process{Pid: pid}, cpuPercent: cpu, rss: rss}
+	}
+	samples := []procSample{
+		sample(1, 90, 10),
+		sample(2, 5, 9000),
+		sample(3, 50, 8000),
+		sample(4, 1, 1),
+	}
+
+	got := selectTop(samples, 2)
+
+	if len(got) != 3 {
+		t.Fatalf("Expected union of 3 processes, got %d", len(got))
+	}
+	if got[0].sample.proc.Pid != 1 || got[1].sample.proc.Pid != 3 || got[2].sample.proc.Pid != 2 {
+		t.Errorf("Unexpected order: %d, %d, %d", got[0].sample.proc.Pid, got[1].sample.proc.Pid, got[2].sample.proc.Pid)
+	}
+	if len(got[1].by) != 2 || got[1].by[0] != "cpu" || got[1].by[1] != "rss" {
+		t.Errorf("pid 3 should be top by cpu and rss, got %v", got[1].by)
+	}
+}
+
+func TestProcessCPUPercent(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	created := start.Add(-100 * time.Second).UnixMilli()
+
+	// No previous sample: lifetime average, 50s of CPU over 100s
+	first := procCPU{createTime: created, cpuSeconds: 50, taken: start}
+	if got := processCPUPercent(procCPU{}, first); got != 50 {
+		t.Errorf("Expected lifetime 50%%, got %v", got)
+	}
+
+	// 30s of CPU over a 20s cycle: 1.5 cores busy
+	second := procCPU{createTime: created, cpuSeconds: 80, taken: start.Add(20 * time.Second)}
+	if got := processCPUPercent(first, second); got != 150 {
+		t.Errorf("Expected 150%%, got %v", got)
+	}
+
+	// Same pid, different process
+	reused := procCPU{createTime: start.UnixMilli(), cpuSeconds: 2, taken: start.Add(20 * time.Second)}
+	if got := processCPUPercent(first, reused); got != 10 {
+		t.Errorf("Expected lifetime 10%% after pid reuse, got %v", got)
+	}
+}
+
+func TestProcessAllowlist(t *testing.T) {
+	c := &processCollector{names: []string{"kafka*", "java"}}
+	if !c.allowed("kafka-server") || !c.allowed("java") || c.allowed("bash") {
+		t.Error("Process name allowlist not applied")
+	}
+}
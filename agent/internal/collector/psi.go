@@ -0,0 +1,103 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gomon/agent/internal/config"
+	pb "gomon/pb"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	Register("psi", func(cfg config.Config) Collector {
+		return &psiCollector{procRoot: cfg.Host.Proc}
+	})
+}
+
+var psiResources = []string{"cpu", "memory", "io"}
+
+// psiCollector reports pressure stall information, the share of time tasks
+// waited on CPU, memory or I/O. It needs a kernel built with CONFIG_PSI.
+type psiCollector struct {
+	procRoot string
+}
+
+func (c *psiCollector) Name() string { return "psi" }
+
+func (c *psiCollector) Collect(ctx context.Context) (*pb.Metric, error) {
+	log.Printf("%s: Collect PSI stats...", logGoroutineInfo())
+
+	metric := &pb.Metric{}
+	var errs []error
+	for _, resource := range psiResources {
+		data, err := os.ReadFile(filepath.Join(c.procRoot, "pressure", resource))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		stats, err := parsePressure(resource, string(data))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metric.PressureStats = append(metric.PressureStats, stats...)
+	}
+
+	// Older kernels have no memory or io pressure, only fail when nothing was readable
+	if len(metric.PressureStats) == 0 {
+		return nil, fmt.Errorf("pressure stall information unavailable: %w", errors.Join(errs...))
+	}
+
+	for _, stats := range metric.PressureStats {
+		log.Printf("%s: Pressure: %s %s, avg10: %.2f%%, avg60: %.2f%%, avg300: %.2f%%, total: %.2fs\n",
+			logGoroutineInfo(), stats.Resource, stats.Kind, stats.Avg10, stats.Avg60, stats.Avg300, stats.TotalSeconds)
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("pressure_lines", len(metric.PressureStats)))
+
+	return metric, nil
+}
+
+// parsePressure parses lines like "some avg10=0.12 avg60=0.05 avg300=0.01 total=123456",
+// total being microseconds
+func parsePressure(resource, data string) ([]*pb.PressureStats, error) {
+	var result []*pb.PressureStats
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		stats := &pb.PressureStats{Resource: resource, Kind: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("%s pressure: malformed field %q", resource, field)
+			}
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s pressure: %s: %w", resource, key, err)
+			}
+			switch key {
+			case "avg10":
+				stats.Avg10 = n
+			case "avg60":
+				stats.Avg60 = n
+			case "avg300":
+				stats.Avg300 = n
+			case "total":
+				stats.TotalSeconds = n / 1e6
+			}
+		}
+		result = append(result, stats)
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePressure(t *testing.T) {
+	data := "some avg10=2.63 avg60=2.47 avg300=5.12 total=432668663\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"
+
+	stats, err := parsePressure("memory", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Expected some and full lines, got %d", len(stats))
+	}
+	some := stats[0]
+	if some.Resource != "memory" || some.Kind != "some" || some.Avg10 != 2.63 || some.Avg300 != 5.12 {
+		t.Errorf("Unexpected some line: %+v", some)
+	}
+	if some.TotalSeconds != 432.668663 {
+		t.Errorf("Expected total in seconds, got %v", some.TotalSeconds)
+	}
+	if stats[1].Kind != "full" {
+		t.Errorf("Unexpected full line: %+v", stats[1])
+	}
+
+	if _, err := parsePressure("cpu", "some avg10=abc"); err == nil {
+		t.Error("Expected error for malformed value")
+	}
+}
+
+func TestPSICollectorPartialSupport(t *testing.T) {
+	procRoot := t.TempDir()
+	if err := os.Mkdir(filepath.Join(procRoot, "pressure"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &psiCollector{procRoot: procRoot}
+	if _, err := c.Collect(context.Background()); err == nil {
+		t.Fatal("Expected error when no pressure file is readable")
+	}
+
+	// Kernels before 5.13 have no full line for cpu
+	cpu := "some avg10=1.00 avg60=0.50 avg300=0.25 total=1000000\n"
+	if err := os.WriteFile(filepath.Join(procRoot, "pressure", "cpu"), []byte(cpu), 0644); err != nil {
+		t.Fatal(err)
+	}
+	metric, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect failed with cpu pressure available: %v", err)
+	}
+	if len(metric.PressureStats) != 1 || metric.PressureStats[0].TotalSeconds != 1 {
+		t.Errorf("Unexpected pressure stats: %+v", metric.PressureStats)
+	}
+}
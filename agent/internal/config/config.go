@@ -0,0 +1,616 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"gomon/kafka"
+	"gomon/schema"
+	"gomon/tracing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LogStdout is the log path value that sends agent logs to stdout instead of a file
+const LogStdout = "stdout"
+
+type Config struct {
+	Interval    time.Duration    `yaml:"interval"`
+	MetricsPort int              `yaml:"metrics_port"`
+	Log         LogConfig        `yaml:"log"`
+	Kafka       KafkaConfig      `yaml:"kafka"`
+	Tracing     tracing.Config   `yaml:"tracing"`
+	Host        HostConfig       `yaml:"host"`
+	Identity    IdentityConfig   `yaml:"identity"`
+	Labels      LabelsConfig     `yaml:"labels"`
+	Spool       SpoolConfig      `yaml:"spool"`
+	Collectors  CollectorsConfig `yaml:"collectors"`
+}
+
+type LogConfig struct {
+	Path string `yaml:"path"`
+}
+
+type KafkaConfig struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+
+	BatchSize    int           `yaml:"batch_size"`
+	BatchBytes   int64         `yaml:"batch_bytes"`
+	Linger       time.Duration `yaml:"linger"`
+	Compression  string        `yaml:"compression"` // none, gzip, snappy, lz4, zstd
+	Acks         string        `yaml:"acks"`        // all, one, none
+	Retries      int           `yaml:"retries"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	// Async publishes without waiting for the broker, failed batches are spooled from the delivery callback
+	Async bool `yaml:"async"`
+	// SchemaVersion of published payloads, 1 (pb.Metric) until every aggregator reads 2 (pb.MetricBatch)
+	SchemaVersion uint32 `yaml:"schema_version"`
+
+	// tls and sasl sections, see kafka.SecurityConfig
+	Security kafka.SecurityConfig `yaml:",inline"`
+}
+
+// SpoolConfig bounds the on-disk buffer used while Kafka is unavailable
+type SpoolConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Dir      string        `yaml:"dir"`
+	MaxBytes int64         `yaml:"max_bytes"`
+	MaxAge   time.Duration `yaml:"max_age"`
+}
+
+// HostConfig tells collectors where the host's filesystems are mounted.
+// The defaults read the live host; in a pod they point at hostPath mounts.
+type HostConfig struct {
+	Proc string `yaml:"proc"`
+	Sys  string `yaml:"sys"`
+	// Root is prefixed to mountpoints when measuring filesystem usage
+	Root string `yaml:"root"`
+}
+
+// IdentityConfig overrides what the agent detects about the host it reports for.
+// Empty fields are detected from the host filesystem.
+type IdentityConfig struct {
+	Hostname        string `yaml:"hostname"`
+	NodeName        string `yaml:"node_name"`
+	CloudProvider   string `yaml:"cloud_provider"`
+	CloudInstanceID string `yaml:"cloud_instance_id"`
+}
+
+// LabelsConfig selects the host labels attached to every payload.
+// Static labels win over discovered ones with the same name.
+type LabelsConfig struct {
+	Static map[string]string `yaml:"static"`
+	// Files in downward API format (key="value" per line), e.g. a projected metadata.labels
+	DownwardAPIFiles []string `yaml:"downward_api_files"`
+	// Keys kept from the downward API files, empty keeps all of them
+	DownwardAPIKeys []string            `yaml:"downward_api_keys"`
+	CloudMetadata   CloudMetadataConfig `yaml:"cloud_metadata"`
+	// OS adds os, os_version and kernel from the host root and proc
+	OS bool `yaml:"os"`
+}
+
+// CloudMetadataConfig queries the instance metadata service of the cloud found in
+// the host identity for region, zone and instance_type
+type CloudMetadataConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Endpoint string        `yaml:"endpoint"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+type CollectorsConfig struct {
+	CPU     CPUConfig     `yaml:"cpu"`
+	Memory  MemoryConfig  `yaml:"memory"`
+	Disk    DiskConfig    `yaml:"disk"`
+	DiskIO  DiskIOConfig  `yaml:"diskio"`
+	Network NetworkConfig `yaml:"network"`
+	Process ProcessConfig `yaml:"process"`
+	Cgroup  CgroupConfig  `yaml:"cgroup"`
+	PSI     PSIConfig     `yaml:"psi"`
+}
+
+// CollectorConfig holds the settings every collector shares
+type CollectorConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type CPUConfig struct {
+	CollectorConfig `yaml:",inline"`
+	SampleWindow    time.Duration `yaml:"sample_window"`
+}
+
+type MemoryConfig struct {
+	CollectorConfig `yaml:",inline"`
+}
+
+type DiskConfig struct {
+	CollectorConfig `yaml:",inline"`
+	// Mountpoint globs; a trailing "/**" matches the whole subtree.
+	// Empty include lists mean "everything not excluded".
+	IncludeMountpoints []string `yaml:"include_mountpoints"`
+	ExcludeMountpoints []string `yaml:"exclude_mountpoints"`
+	IncludeFstypes     []string `yaml:"include_fstypes"`
+	ExcludeFstypes     []string `yaml:"exclude_fstypes"`
+}
+
+type DiskIOConfig struct {
+	CollectorConfig `yaml:",inline"`
+	// Device name globs to skip, e.g. loop*
+	ExcludeDevices []string `yaml:"exclude_devices"`
+}
+
+type NetworkConfig struct {
+	CollectorConfig `yaml:",inline"`
+}
+
+type ProcessConfig struct {
+	CollectorConfig `yaml:",inline"`
+	// Number of processes reported by CPU and by RSS each
+	TopN int `yaml:"top_n"`
+	// Process name globs to consider, empty means all processes
+	Names []string `yaml:"names"`
+}
+
+type CgroupConfig struct {
+	CollectorConfig `yaml:",inline"`
+	// Mountpoint of the cgroup filesystem, empty means <host.sys>/fs/cgroup
+	Root string `yaml:"root"`
+}
+
+type PSIConfig struct {
+	CollectorConfig `yaml:",inline"`
+}
+
+// all maps collector names to their shared settings
+func (c *CollectorsConfig) all() map[string]*CollectorConfig {
+	return map[string]*CollectorConfig{
+		"cpu":     &c.CPU.CollectorConfig,
+		"memory":  &c.Memory.CollectorConfig,
+		"disk":    &c.Disk.CollectorConfig,
+		"diskio":  &c.DiskIO.CollectorConfig,
+		"network": &c.Network.CollectorConfig,
+		"process": &c.Process.CollectorConfig,
+		"cgroup":  &c.Cgroup.CollectorConfig,
+		"psi":     &c.PSI.CollectorConfig,
+	}
+}
+
+// Lookup returns the shared settings of the named collector
+func (c CollectorsConfig) Lookup(name string) (CollectorConfig, bool) {
+	settings, ok := c.all()[name]
+	if !ok {
+		return CollectorConfig{}, false
+	}
+	return *settings, true
+}
+
+// Default returns the settings used where the config file and environment set
+// none: a 20s interval, batched snappy Kafka writes of v1 payloads, an on-disk
+// spool, host paths at /, every collector enabled and cloud and OS host labels
+func Default() Config {
+	return Config{
+		Interval:    20 * time.Second,
+		MetricsPort: 2112,
+		Log: LogConfig{
+			Path: "/var/log/agent.log",
+		},
+		Kafka: KafkaConfig{
+			BatchSize:     100,
+			Linger:        10 * time.Millisecond,
+			Compression:   "snappy",
+			Acks:          "all",
+			Retries:       10,
+			WriteTimeout:  10 * time.Second,
+			SchemaVersion: schema.V1,
+		},
+		Tracing: tracing.DefaultConfig("gomon-agent"),
+		Labels: LabelsConfig{
+			CloudMetadata: CloudMetadataConfig{
+				Enabled:  true,
+				Endpoint: "http://169.254.169.254",
+				Timeout:  2 * time.Second,
+			},
+			OS: true,
+		},
+		Spool: SpoolConfig{
+			Enabled:  true,
+			Dir:      "/var/lib/gomon/spool",
+			MaxBytes: 64 << 20,
+			MaxAge:   24 * time.Hour,
+		},
+		Host: HostConfig{
+			Proc: "/proc",
+			Sys:  "/sys",
+			Root: "/",
+		},
+		Collectors: CollectorsConfig{
+			CPU: CPUConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 5 * time.Second},
+				SampleWindow:    time.Second,
+			},
+			Memory: MemoryConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 5 * time.Second},
+			},
+			Disk: DiskConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 10 * time.Second},
+				ExcludeMountpoints: []string{
+					"/etc/**", "/dev/**", "/proc/**", "/sys/**", "/run/**",
+					"/var/lib/kubelet/pods/**", "/var/lib/docker/**", "/var/lib/containerd/**",
+				},
+				ExcludeFstypes: []string{"overlay", "tmpfs", "squashfs", "nsfs", "devtmpfs", "proc", "sysfs", "cgroup", "cgroup2"},
+			},
+			DiskIO: DiskIOConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 5 * time.Second},
+				ExcludeDevices:  []string{"loop*", "ram*"},
+			},
+			Network: NetworkConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 5 * time.Second},
+			},
+			Process: ProcessConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 10 * time.Second},
+				TopN:            5,
+			},
+			Cgroup: CgroupConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 10 * time.Second},
+			},
+			PSI: PSIConfig{
+				CollectorConfig: CollectorConfig{Enabled: true, Timeout: 5 * time.Second},
+			},
+		},
+	}
+}
+
+// Load reads the agent configuration from CONFIG_PATH (or configs/agent.yaml),
+// applies environment overrides and validates the result.
+// A missing default config file is not an error: the agent then runs on defaults + env.
+func Load() (Config, error) {
+	config := Default()
+
+	configPath := os.Getenv("CONFIG_PATH")
+	explicit := configPath != ""
+
+	if !explicit {
+		// Default: try relative path from project root
+		configPath = "configs/agent.yaml"
+
+		// If that doesn't exist, try from repository root
+		if _, err := os.Stat(configPath); os.IsNotExist(err) {
+			configPath = "agent/configs/agent.yaml"
+		}
+	}
+
+	byteYaml, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(byteYaml, &config); err != nil {
+			return Config{}, fmt.Errorf("could not unmarshal config: %w", err)
+		}
+	case explicit || !errors.Is(err, os.ErrNotExist):
+		return Config{}, fmt.Errorf("could not read %s: %w", configPath, err)
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return Config{}, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, nil
+}
+
+// applyEnv overrides file values with environment variables
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("AGENT_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("could not parse AGENT_INTERVAL: %w", err)
+		}
+		c.Interval = d
+	}
+
+	if v := os.Getenv("METRICS_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("could not parse METRICS_PORT: %w", err)
+		}
+		c.MetricsPort = port
+	}
+
+	if v := os.Getenv("LOG_FILE"); v != "" {
+		c.Log.Path = v
+	}
+
+	if v := os.Getenv("KAFKA_BROKERS"); v != "" {
+		c.Kafka.Brokers = strings.Split(v, ",")
+	}
+
+	if v := os.Getenv("KAFKA_TOPIC"); v != "" {
+		c.Kafka.Topic = v
+	}
+
+	if v := os.Getenv("KAFKA_BATCH_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_BATCH_SIZE: %w", err)
+		}
+		c.Kafka.BatchSize = n
+	}
+
+	if v := os.Getenv("KAFKA_LINGER"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_LINGER: %w", err)
+		}
+		c.Kafka.Linger = d
+	}
+
+	if v := os.Getenv("KAFKA_COMPRESSION"); v != "" {
+		c.Kafka.Compression = v
+	}
+
+	if v := os.Getenv("KAFKA_ACKS"); v != "" {
+		c.Kafka.Acks = v
+	}
+
+	if v := os.Getenv("KAFKA_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_RETRIES: %w", err)
+		}
+		c.Kafka.Retries = n
+	}
+
+	if v := os.Getenv("KAFKA_REQUEST_TIMEOUT_MS"); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_REQUEST_TIMEOUT_MS: %w", err)
+		}
+		c.Kafka.WriteTimeout = time.Duration(ms) * time.Millisecond
+	}
+
+	if v := os.Getenv("KAFKA_ASYNC"); v != "" {
+		async, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_ASYNC: %w", err)
+		}
+		c.Kafka.Async = async
+	}
+
+	if v := os.Getenv("KAFKA_SCHEMA_VERSION"); v != "" {
+		version, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("could not parse KAFKA_SCHEMA_VERSION: %w", err)
+		}
+		c.Kafka.SchemaVersion = uint32(version)
+	}
+
+	// KAFKA_TLS_* and KAFKA_SASL_*, the SASL password is only read from env or file
+	if err := c.Kafka.Security.ApplyEnv(); err != nil {
+		return err
+	}
+
+	// TRACING_*, see the tracing package
+	if err := c.Tracing.ApplyEnv(); err != nil {
+		return err
+	}
+
+	if v := os.Getenv("SPOOL_DIR"); v != "" {
+		c.Spool.Dir = v
+	}
+
+	// Same names gopsutil uses, so existing container setups keep working
+	if v := os.Getenv("HOST_PROC"); v != "" {
+		c.Host.Proc = v
+	}
+
+	if v := os.Getenv("HOST_SYS"); v != "" {
+		c.Host.Sys = v
+	}
+
+	if v := os.Getenv("HOST_ROOT"); v != "" {
+		c.Host.Root = v
+	}
+
+	if v := os.Getenv("CGROUP_ROOT"); v != "" {
+		c.Collectors.Cgroup.Root = v
+	}
+
+	// NODE_NAME comes from the downward API (spec.nodeName)
+	identity := map[string]*string{
+		"AGENT_HOSTNAME":    &c.Identity.Hostname,
+		"NODE_NAME":         &c.Identity.NodeName,
+		"CLOUD_PROVIDER":    &c.Identity.CloudProvider,
+		"CLOUD_INSTANCE_ID": &c.Identity.CloudInstanceID,
+	}
+	for name, field := range identity {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+		}
+	}
+
+	if err := c.Labels.applyEnv(); err != nil {
+		return err
+	}
+
+	// Comma separated list of collectors to run, everything else is disabled
+	if v := os.Getenv("AGENT_COLLECTORS"); v != "" {
+		enabled := make(map[string]bool)
+		for _, name := range strings.Split(v, ",") {
+			enabled[strings.TrimSpace(name)] = true
+		}
+		collectors := c.Collectors.all()
+		for name := range enabled {
+			if _, ok := collectors[name]; !ok {
+				return fmt.Errorf("AGENT_COLLECTORS: unknown collector %q", name)
+			}
+		}
+		for name, settings := range collectors {
+			settings.Enabled = enabled[name]
+		}
+	}
+
+	return nil
+}
+
+// Validate reports the first configuration problem found
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.Interval)
+	}
+
+	if c.MetricsPort <= 0 || c.MetricsPort > 65535 {
+		return fmt.Errorf("metrics_port must be between 1 and 65535, got %d", c.MetricsPort)
+	}
+
+	if c.Log.Path == "" {
+		return errors.New("log.path must be set (file path or \"stdout\")")
+	}
+
+	if len(c.Kafka.Brokers) == 0 {
+		return errors.New("kafka.brokers must be set (or KAFKA_BROKERS)")
+	}
+	for _, broker := range c.Kafka.Brokers {
+		if strings.TrimSpace(broker) == "" {
+			return errors.New("kafka.brokers contains an empty address")
+		}
+	}
+
+	if c.Kafka.Topic == "" {
+		return errors.New("kafka.topic must be set (or KAFKA_TOPIC)")
+	}
+
+	if c.Kafka.BatchSize < 0 || c.Kafka.BatchBytes < 0 || c.Kafka.Linger < 0 || c.Kafka.Retries < 0 || c.Kafka.WriteTimeout < 0 {
+		return errors.New("kafka batch_size, batch_bytes, linger, retries and write_timeout must not be negative")
+	}
+	if _, err := kafka.ParseCompression(c.Kafka.Compression); err != nil {
+		return fmt.Errorf("kafka.compression: %w", err)
+	}
+	if _, err := kafka.ParseAcks(c.Kafka.Acks); err != nil {
+		return fmt.Errorf("kafka.acks: %w", err)
+	}
+	if c.Kafka.SchemaVersion != schema.V1 && c.Kafka.SchemaVersion != schema.V2 {
+		return fmt.Errorf("kafka.schema_version must be %d or %d, got %d", schema.V1, schema.V2, c.Kafka.SchemaVersion)
+	}
+	if err := c.Kafka.Security.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	for name := range c.Labels.Static {
+		if err := schema.ValidateHostLabel(name); err != nil {
+			return fmt.Errorf("labels.static: %w", err)
+		}
+	}
+	if c.Labels.CloudMetadata.Enabled && c.Labels.CloudMetadata.Timeout <= 0 {
+		return fmt.Errorf("labels.cloud_metadata.timeout must be positive, got %s", c.Labels.CloudMetadata.Timeout)
+	}
+
+	enabledCount := 0
+	for name, settings := range c.Collectors.all() {
+		if !settings.Enabled {
+			continue
+		}
+		enabledCount++
+		if settings.Timeout <= 0 {
+			return fmt.Errorf("collectors.%s.timeout must be positive, got %s", name, settings.Timeout)
+		}
+	}
+	if enabledCount == 0 {
+		return errors.New("at least one collector must be enabled")
+	}
+
+	globs := map[string][]string{
+		"collectors.disk.include_mountpoints": c.Collectors.Disk.IncludeMountpoints,
+		"collectors.disk.exclude_mountpoints": c.Collectors.Disk.ExcludeMountpoints,
+		"collectors.diskio.exclude_devices":   c.Collectors.DiskIO.ExcludeDevices,
+		"collectors.process.names":            c.Collectors.Process.Names,
+	}
+	for field, patterns := range globs {
+		for _, pattern := range patterns {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+				return fmt.Errorf("%s: bad pattern %q: %w", field, pattern, err)
+			}
+		}
+	}
+
+	if c.Collectors.Process.Enabled && c.Collectors.Process.TopN <= 0 {
+		return fmt.Errorf("collectors.process.top_n must be positive, got %d", c.Collectors.Process.TopN)
+	}
+
+	if c.Spool.Enabled {
+		if c.Spool.Dir == "" {
+			return errors.New("spool.dir must be set when the spool is enabled")
+		}
+		if c.Spool.MaxBytes <= 0 {
+			return fmt.Errorf("spool.max_bytes must be positive, got %d", c.Spool.MaxBytes)
+		}
+		if c.Spool.MaxAge <= 0 {
+			return fmt.Errorf("spool.max_age must be positive, got %s", c.Spool.MaxAge)
+		}
+	}
+
+	if c.Host.Proc == "" || c.Host.Sys == "" || c.Host.Root == "" {
+		return fmt.Errorf("host.proc, host.sys and host.root must be set, got %+v", c.Host)
+	}
+
+	cpuCfg := c.Collectors.CPU
+	if cpuCfg.Enabled && (cpuCfg.SampleWindow <= 0 || cpuCfg.SampleWindow >= c.Interval) {
+		return fmt.Errorf("collectors.cpu.sample_window must be positive and shorter than interval, got %s", cpuCfg.SampleWindow)
+	}
+
+	return nil
+}
+
+// CgroupRoot returns where the cgroup filesystem is mounted
+func (c Config) CgroupRoot() string {
+	if c.Collectors.Cgroup.Root != "" {
+		return c.Collectors.Cgroup.Root
+	}
+	return path.Join(c.Host.Sys, "fs", "cgroup")
+}
+
+// applyEnv merges AGENT_LABELS (name=value,...) into the static labels and
+// overrides the discovery settings
+func (l *LabelsConfig) applyEnv() error {
+	if v := os.Getenv("AGENT_LABELS"); v != "" {
+		if l.Static == nil {
+			l.Static = make(map[string]string)
+		}
+		for _, pair := range strings.Split(v, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("AGENT_LABELS: %q is not name=value", pair)
+			}
+			l.Static[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	if v := os.Getenv("LABELS_DOWNWARD_API_FILES"); v != "" {
+		l.DownwardAPIFiles = strings.Split(v, ",")
+	}
+
+	if v := os.Getenv("LABELS_DOWNWARD_API_KEYS"); v != "" {
+		l.DownwardAPIKeys = strings.Split(v, ",")
+	}
+
+	if v := os.Getenv("CLOUD_METADATA_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("could not parse CLOUD_METADATA_ENABLED: %w", err)
+		}
+		l.CloudMetadata.Enabled = enabled
+	}
+
+	return nil
+}
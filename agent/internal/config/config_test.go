@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("CONFIG_PATH", "../../configs/agent.yaml")
+	t.Setenv("KAFKA_BROKERS", "")
+	t.Setenv("KAFKA_TOPIC", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Interval != 20*time.Second {
+		t.Errorf("Expected Interval=20s, got %s", cfg.Interval)
+	}
+
+	if len(cfg.Kafka.Brokers) != 3 {
+		t.Errorf("Expected 3 Kafka brokers, got %d", len(cfg.Kafka.Brokers))
+	}
+
+	if cfg.Collectors.CPU.SampleWindow != time.Second {
+		t.Errorf("Expected CPU SampleWindow=1s, got %s", cfg.Collectors.CPU.SampleWindow)
+	}
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	t.Setenv("CONFIG_PATH", "../../configs/agent.yaml")
+	t.Setenv("AGENT_INTERVAL", "1m")
+	t.Setenv("KAFKA_BROKERS", "broker-a:9092,broker-b:9092")
+	t.Setenv("KAFKA_TOPIC", "metrics-test")
+	t.Setenv("LOG_FILE", LogStdout)
+	t.Setenv("AGENT_COLLECTORS", "cpu,memory")
+	t.Setenv("HOST_PROC", "/host/proc")
+	t.Setenv("HOST_SYS", "/host/sys")
+	t.Setenv("KAFKA_SCHEMA_VERSION", "2")
+	t.Setenv("AGENT_LABELS", "env=prod, team=core")
+	t.Setenv("CLOUD_METADATA_ENABLED", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Interval != time.Minute {
+		t.Errorf("Expected Interval=1m, got %s", cfg.Interval)
+	}
+	if len(cfg.Kafka.Brokers) != 2 || cfg.Kafka.Brokers[0] != "broker-a:9092" {
+		t.Errorf("Unexpected brokers: %v", cfg.Kafka.Brokers)
+	}
+	if cfg.Kafka.Topic != "metrics-test" {
+		t.Errorf("Expected topic metrics-test, got %s", cfg.Kafka.Topic)
+	}
+	if cfg.Kafka.SchemaVersion != 2 {
+		t.Errorf("Expected schema version 2, got %d", cfg.Kafka.SchemaVersion)
+	}
+	if cfg.Labels.Static["env"] != "prod" || cfg.Labels.Static["team"] != "core" || cfg.Labels.CloudMetadata.Enabled {
+		t.Errorf("Unexpected labels config: %+v", cfg.Labels)
+	}
+	if cfg.Log.Path != LogStdout {
+		t.Errorf("Expected log path stdout, got %s", cfg.Log.Path)
+	}
+	if !cfg.Collectors.CPU.Enabled || !cfg.Collectors.Memory.Enabled ||
+		cfg.Collectors.Disk.Enabled || cfg.Collectors.Network.Enabled {
+		t.Errorf("Unexpected collectors: %+v", cfg.Collectors)
+	}
+	if cfg.Host.Proc != "/host/proc" || cfg.Host.Root != "/" {
+		t.Errorf("Unexpected host paths: %+v", cfg.Host)
+	}
+	if cfg.CgroupRoot() != "/host/sys/fs/cgroup" {
+		t.Errorf("Expected cgroup root derived from HOST_SYS, got %s", cfg.CgroupRoot())
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	body := "interval: 5s\nkafka:\n  brokers: [localhost:9092]\n  topic: metrics\ncollectors:\n  cpu:\n    sample_window: 10s\n"
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_PATH", path)
+	t.Setenv("KAFKA_BROKERS", "")
+	t.Setenv("KAFKA_TOPIC", "")
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for cpu sample_window longer than interval")
+	}
+
+	t.Setenv("CONFIG_PATH", "../../configs/agent.yaml")
+	t.Setenv("KAFKA_COMPRESSION", "brotli")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unknown kafka compression")
+	}
+
+	t.Setenv("KAFKA_COMPRESSION", "")
+	t.Setenv("KAFKA_SCHEMA_VERSION", "3")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for unknown schema version")
+	}
+
+	t.Setenv("KAFKA_SCHEMA_VERSION", "")
+	t.Setenv("AGENT_LABELS", "instance=other")
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for reserved static label")
+	}
+}
@@ -0,0 +1,280 @@
+// Package spool keeps marshalled metrics on local disk while Kafka is unreachable
+// and hands them back, oldest first, once it recovers.
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const recordExt = ".metric"
+
+var (
+	spoolRecords = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gomon_agent_spool_records",
+		Help: "Number of payloads waiting in the on-disk spool",
+	})
+	spoolBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gomon_agent_spool_bytes",
+		Help: "Size of the payloads waiting in the on-disk spool",
+	})
+	spoolDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_agent_spool_dropped_total",
+			Help: "Payloads dropped from the spool by reason (size, age, corrupt)",
+		},
+		[]string{"reason"},
+	)
+	spoolReplayed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gomon_agent_spool_replayed_total",
+		Help: "Payloads successfully replayed from the spool",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(spoolRecords)
+	prometheus.MustRegister(spoolBytes)
+	prometheus.MustRegister(spoolDropped)
+	prometheus.MustRegister(spoolReplayed)
+}
+
+type record struct {
+	name    string
+	size    int64
+	created time.Time
+}
+
+// Spool is a bounded FIFO of payloads, one file per payload. File names carry
+// the creation time and a sequence number so order and age survive restarts.
+type Spool struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	// replayMu keeps one replay at a time, mu is only held between sends
+	replayMu sync.Mutex
+
+	mu      sync.Mutex
+	records []record
+	bytes   int64
+	seq     uint64
+	now     func() time.Time
+}
+
+// Open creates dir if needed and loads the payloads a previous run left behind
+func Open(dir string, maxBytes int64, maxAge time.Duration) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create spool directory: %w", err)
+	}
+
+	s := &Spool{dir: dir, maxBytes: maxBytes, maxAge: maxAge, now: time.Now}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read spool directory: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasSuffix(name, ".tmp") {
+			// Interrupted write, the payload was never acknowledged as spooled
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		created, seq, ok := parseName(name)
+		if !ok {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		s.records = append(s.records, record{name: name, size: info.Size(), created: created})
+		s.bytes += info.Size()
+		if seq >= s.seq {
+			s.seq = seq + 1
+		}
+	}
+	sort.Slice(s.records, func(i, j int) bool { return s.records[i].name < s.records[j].name })
+
+	s.mu.Lock()
+	s.expire()
+	s.updateGauges()
+	s.mu.Unlock()
+
+	if len(s.records) > 0 {
+		log.Printf("Spool: found %d payloads (%d bytes) from a previous run in %s", len(s.records), s.bytes, dir)
+	}
+	return s, nil
+}
+
+// Append stores a payload, evicting the oldest ones when the size limit is hit
+func (s *Spool) Append(data []byte) error {
+	return s.Insert(data, s.now())
+}
+
+// Insert stores a payload collected at created in its place among the spooled
+// ones, so a payload whose delivery failed late is replayed in collection order
+func (s *Spool) Insert(data []byte, created time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := int64(len(data))
+	if size > s.maxBytes {
+		spoolDropped.WithLabelValues("size").Inc()
+		return fmt.Errorf("payload of %d bytes exceeds spool limit of %d bytes", size, s.maxBytes)
+	}
+
+	s.expire()
+	for s.bytes+size > s.maxBytes && len(s.records) > 0 {
+		s.remove(0)
+		spoolDropped.WithLabelValues("size").Inc()
+	}
+
+	name := fmt.Sprintf("%020d-%010d%s", created.UnixNano(), s.seq, recordExt)
+	s.seq++
+
+	// Write then rename so a crash never leaves a truncated record behind
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("could not write spool record: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return fmt.Errorf("could not commit spool record: %w", err)
+	}
+
+	i := sort.Search(len(s.records), func(i int) bool { return s.records[i].name > name })
+	s.records = append(s.records, record{})
+	copy(s.records[i+1:], s.records[i:])
+	s.records[i] = record{name: name, size: size, created: created}
+	s.bytes += size
+	s.updateGauges()
+	return nil
+}
+
+// Replay hands spooled payloads to send oldest first and removes each one
+// send accepted, so send must only return once the payload is delivered. The
+// head is sent alone first: when it fails, or ctx is done, Replay stops and
+// leaves that payload and everything after it in place. The spool is not
+// locked during a send, Append and Insert go on meanwhile.
+func (s *Spool) Replay(ctx context.Context, send func(context.Context, []byte) error) (int, error) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	replayed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+
+		s.mu.Lock()
+		s.expire()
+		s.updateGauges()
+		if len(s.records) == 0 {
+			s.mu.Unlock()
+			return replayed, nil
+		}
+		name := s.records[0].name
+		s.mu.Unlock()
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil || len(data) == 0 {
+			log.Printf("Spool: dropping unreadable record %s: %v", name, err)
+			s.removeName(name)
+			spoolDropped.WithLabelValues("corrupt").Inc()
+			continue
+		}
+		if err := send(ctx, data); err != nil {
+			return replayed, err
+		}
+		s.removeName(name)
+		spoolReplayed.Inc()
+		replayed++
+	}
+}
+
+// Len returns the number of spooled payloads
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// Bytes returns the size of the spooled payloads
+func (s *Spool) Bytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
+
+// expire drops payloads older than maxAge, callers hold mu
+func (s *Spool) expire() {
+	if s.maxAge <= 0 {
+		return
+	}
+	cutoff := s.now().Add(-s.maxAge)
+	for len(s.records) > 0 && s.records[0].created.Before(cutoff) {
+		s.remove(0)
+		spoolDropped.WithLabelValues("age").Inc()
+	}
+}
+
+// removeName deletes a record, unless it was evicted meanwhile
+func (s *Spool) removeName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, r := range s.records {
+		if r.name == name {
+			s.remove(i)
+			break
+		}
+	}
+	s.updateGauges()
+}
+
+// remove deletes the i-th record from disk and from the index, callers hold mu
+func (s *Spool) remove(i int) {
+	r := s.records[i]
+	if err := os.Remove(filepath.Join(s.dir, r.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Spool: could not remove %s: %v", r.name, err)
+	}
+	s.bytes -= r.size
+	s.records = append(s.records[:i], s.records[i+1:]...)
+}
+
+func (s *Spool) updateGauges() {
+	spoolRecords.Set(float64(len(s.records)))
+	spoolBytes.Set(float64(s.bytes))
+}
+
+// parseName splits "<unix nanos>-<seq>.metric"
+func parseName(name string) (time.Time, uint64, bool) {
+	base, ok := strings.CutSuffix(name, recordExt)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	nanosStr, seqStr, ok := strings.Cut(base, "-")
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	nanos, err := strconv.ParseInt(nanosStr, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	return time.Unix(0, nanos), seq, true
+}
@@ -0,0 +1,167 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func collect(t *testing.T, s *Spool) []string {
+	t.Helper()
+	var got []string
+	if _, err := s.Replay(context.Background(), func(ctx context.Context, data []byte) error {
+		got = append(got, string(data))
+		return nil
+	}); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	return got
+}
+
+func TestSpoolReplaysInOrderAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(dir, 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, payload := range []string{"one", "two", "three"} {
+		if err := s.Append([]byte(payload)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Leftover of an interrupted write must be ignored and cleaned up
+	if err := os.WriteFile(filepath.Join(dir, "junk.metric.tmp"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(dir, 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != 3 || reopened.Bytes() != int64(len("onetwothree")) {
+		t.Fatalf("Expected 3 records of 11 bytes, got %d records of %d bytes", reopened.Len(), reopened.Bytes())
+	}
+	if err := reopened.Append([]byte("four")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := collect(t, reopened)
+	want := []string{"one", "two", "three", "four"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Replay order: expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Expected empty spool directory after replay, found %d entries", len(entries))
+	}
+}
+
+func TestSpoolStopsAtFirstFailure(t *testing.T) {
+	s, err := Open(t.TempDir(), 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Append([]byte("a"))
+	s.Append([]byte("b"))
+
+	calls := 0
+	replayed, err := s.Replay(context.Background(), func(ctx context.Context, data []byte) error {
+		calls++
+		if string(data) == "b" {
+			return errors.New("kafka down")
+		}
+		return nil
+	})
+	if err == nil || replayed != 1 || calls != 2 {
+		t.Fatalf("Expected one replayed payload and an error, got %d replayed, err %v", replayed, err)
+	}
+	if got := collect(t, s); len(got) != 1 || got[0] != "b" {
+		t.Errorf("Failed payload must stay spooled, got %v", got)
+	}
+}
+
+func TestSpoolLimits(t *testing.T) {
+	s, err := Open(t.TempDir(), 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	if err := s.Append([]byte("this payload is too large")); err == nil {
+		t.Error("Expected error for payload larger than the spool")
+	}
+
+	s.Append([]byte("aaaa"))
+	s.Append([]byte("bbbb"))
+	s.Append([]byte("cccc")) // evicts "aaaa"
+	if s.Len() != 2 || s.Bytes() != 8 {
+		t.Fatalf("Expected oldest record evicted, got %d records of %d bytes", s.Len(), s.Bytes())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got := collect(t, s); len(got) != 0 {
+		t.Errorf("Expected expired records to be dropped, replayed %v", got)
+	}
+}
+
+func TestSpoolInsertKeepsCollectionOrder(t *testing.T) {
+	s, err := Open(t.TempDir(), 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	s.Append([]byte("b"))
+	now = now.Add(time.Second)
+	s.Append([]byte("c"))
+
+	// A delivery that failed late goes back where it was collected
+	if err := s.Insert([]byte("a"), now.Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if got := collect(t, s); len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Expected a, b, c, got %v", got)
+	}
+}
+
+func TestSpoolReplayDoesNotBlockAppend(t *testing.T) {
+	s, err := Open(t.TempDir(), 1<<20, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Append([]byte("a"))
+	s.Append([]byte("b"))
+
+	// The send waits for Kafka until the deadline, appending goes on meanwhile
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	calls := 0
+	replayed, err := s.Replay(ctx, func(ctx context.Context, data []byte) error {
+		calls++
+		if err := s.Append([]byte("c")); err != nil {
+			t.Errorf("Append during replay failed: %v", err)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || replayed != 0 || calls != 1 {
+		t.Fatalf("Expected only the head tried until the deadline, got %d replayed in %d calls, err %v", replayed, calls, err)
+	}
+	if got := collect(t, s); len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Errorf("Expected every payload kept in order, got %v", got)
+	}
+}
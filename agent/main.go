@@ -1,36 +1,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
-	"google.golang.org/protobuf/proto"
 
 	pb "gomon/pb"
 
 	"strconv"
 
+	"gomon/agent/internal/collector"
+	"gomon/agent/internal/config"
+	"gomon/agent/internal/spool"
 	"gomon/kafka"
+	"gomon/schema"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	"github.com/uber/jaeger-lib/metrics"
+	"gomon/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	kafkago "github.com/segmentio/kafka-go"
 )
 
 func startMetricServer(port string) {
@@ -47,220 +47,19 @@ func startMetricServer(port string) {
 	}()
 }
 
-func logGoroutineInfo() string {
-	buf := make([]byte, 1024)
-	// Capture the stack trace of the current goroutine
-	n := runtime.Stack(buf, false)
-	// Filter and extract only the goroutine info
-	stackTrace := string(buf[:n])
-	lines := strings.Split(stackTrace, "\n")
-
-	// Just return the first line, which includes the goroutine ID and state
-	if len(lines) > 0 {
-		return lines[0]
-	}
-	return ""
-}
-
-func collectCPU(wg *sync.WaitGroup, metrics *pb.Metric, parentSpan opentracing.Span) {
-	defer wg.Done()
-
-	// Span for CPU
-	cpuSpan := opentracing.StartSpan("collect-cpu", opentracing.ChildOf(parentSpan.Context()))
-	defer cpuSpan.Finish()
-
-	log.Printf("%s: Collect CPU stats...", logGoroutineInfo())
-	cpuUsage, err := cpu.Percent(time.Second, false)
-	if err != nil {
-		log.Println("Error getting CPU usage:", err)
-		cpuSpan.SetTag("error", true)
-		return
-	}
-
-	if len(cpuUsage) > 0 {
-		metrics.CpuUsagePercent = float32(cpuUsage[0])
-		cpuSpan.SetTag("cpu_usage_percent", cpuUsage[0])
-	}
-
-	log.Printf("%s: CPU Usage: %.2f%%\n", logGoroutineInfo(), cpuUsage[0])
-}
-
-func collectMemory(wg *sync.WaitGroup, metrics *pb.Metric, parentSpan opentracing.Span) {
-	defer wg.Done()
-
-	// Span for memory
-	memSpan := opentracing.StartSpan("collect-memory", opentracing.ChildOf(parentSpan.Context()))
-	defer memSpan.Finish()
-
-	log.Printf("%s: Collect Memory stats...", logGoroutineInfo())
-	vMem, err := mem.VirtualMemory()
-	if err != nil {
-		log.Println("Error getting memory usage:", err)
-		memSpan.SetTag("error", true)
-		return
-	}
-
-	// Convert to GB (1024 * 1024 * 1024)
-	totalVm := vMem.Total / (1 << 30)
-	usedVm := vMem.Used / (1 << 30)
-	// Convert to Mb (1024 * 1024)
-	freeVm := vMem.Free / (1 << 20)
-	buffers := vMem.Buffers
-	cached := vMem.Cached
-	swapTotal := vMem.SwapTotal
-	swapUsed := vMem.SwapCached
-	swapFree := vMem.SwapFree
-
-	metrics.MemoryTotalGb = totalVm
-	metrics.MemoryUsedPercent = float32(usedVm)
-	metrics.MemoryUsedGb = usedVm
-	metrics.MemoryFreeGb = freeVm
-
-	memSpan.SetTag("memory_used_percent", vMem.UsedPercent)
-	memSpan.SetTag("memory_total_gb", totalVm)
-
-	log.Printf("%s: Memory Usage: %.2f%% (Total: %v Gb, Used: %v Gb, Free: %v Mb, Buffers: %v, Cached: %v),"+
-		"Swap Usage: SwapTotal: %v, SwapUsed: %v, SwapFree: %v\n", logGoroutineInfo(),
-		vMem.UsedPercent, totalVm, usedVm, freeVm, buffers, cached,
-		swapTotal, swapUsed, swapFree)
-}
-
-func collectDisk(wg *sync.WaitGroup, metric *pb.Metric, parentSpan opentracing.Span) {
-	defer wg.Done()
-
-	// Span for disk stats
-	diskSpan := opentracing.StartSpan("collect-disk", opentracing.ChildOf(parentSpan.Context()))
-	defer diskSpan.Finish()
-
-	log.Printf("%s: Collect Disk stats...", logGoroutineInfo())
-	partitions, err := disk.Partitions(false)
-	if err != nil {
-		log.Printf("Error fetching disk partitions: %v\n", err)
-		diskSpan.SetTag("error", true)
-		return
-	}
-
-	var totalDiskSpaceGB uint64
-	var totalDiskUsedGB uint64
-	partitionCount := 0
-
-	for _, partition := range partitions {
-		usage, err := disk.Usage(partition.Mountpoint)
-		if err != nil {
-			log.Printf("Error fetching disk usage: %v\n", err)
-			continue
-		}
-
-		diskTotal := usage.Total / (1 << 30)
-		diskUsage := usage.Used / (1 << 30)
-
-		totalDiskSpaceGB += uint64(diskTotal)
-		totalDiskUsedGB += uint64(diskUsage)
-		partitionCount++
-
-		metric.DiskStats = append(metric.DiskStats, &pb.DiskUsage{
-			Mountpoint:  partition.Mountpoint,
-			UsedPercent: float32(usage.UsedPercent),
-			TotalGb:     usage.Total,
-			UsedGb:      usage.Used,
-		})
-
-		diskSpan.SetTag("partitions_processed", partitionCount)
-		diskSpan.SetTag("total_disk_space_gb", totalDiskSpaceGB)
-		diskSpan.SetTag("total_disk_used_gb", totalDiskUsedGB)
-		if totalDiskSpaceGB > 0 {
-			diskUsagePercent := float64(totalDiskUsedGB) / float64(totalDiskSpaceGB) * 100
-			diskSpan.SetTag("total_disk_used_percent", diskUsagePercent)
-		}
-	}
-
-}
-
-func collectNet(wg *sync.WaitGroup, metric *pb.Metric, parentSpan opentracing.Span) {
-	defer wg.Done()
-
-	// Span for net stats
-	netSpan := opentracing.StartSpan("collect-network", opentracing.ChildOf(parentSpan.Context()))
-	defer netSpan.Finish()
-
-	log.Printf("%s: Collect Network stats...", logGoroutineInfo())
-	// Get initial network stats
-	prevCounters, err := net.IOCounters(false)
-	if err != nil || len(prevCounters) == 0 {
-		log.Println("Error fetching initial network stats:", err)
-		return
-	}
-
-	// Wait for the interval duration (e.g., 10 seconds)
-	time.Sleep(20 * time.Second)
-
-	// Get network stats after the interval
-	currCounters, err := net.IOCounters(false)
-	if err != nil || len(currCounters) == 0 {
-		log.Println("Error fetching current network stats:", err)
-		return
-	}
-
-	// Calculate delta (difference)
-	for i, curr := range currCounters {
-		prev := prevCounters[i]
-		bytesSentDelta := curr.BytesSent - prev.BytesSent
-		bytesRecvDelta := curr.BytesRecv - prev.BytesRecv
-
-		metric.NetStats = append(metric.NetStats, &pb.NetworkUsage{
-			InterfaceName: curr.Name,
-			BytesSent:     curr.BytesSent,
-			BytesReceived: curr.BytesRecv,
-		})
-
-		log.Printf("%s: Interface: %s\n", logGoroutineInfo(), curr.Name)
-		log.Printf("%s: Sent: %.2f Bytes, Received: %.2f Bytes\n", logGoroutineInfo(),
-			float64(bytesSentDelta), float64(bytesRecvDelta))
-
-	}
-
-	netSpan.SetTag("interfaces_processed", len(metric.NetStats))
-}
-
-// Jaeger
-func initJaeger() (opentracing.Tracer, func(), error) {
-	cfg := jaegercfg.Configuration{
-		ServiceName: "gomon-agent",
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1, // Sample 100% of traces for development
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans:          true,                             // Enable span logging for debugging
-			CollectorEndpoint: "http://jaeger:14268/api/traces", // Jaeger agent HTTP endpoint
-		},
-	}
-
-	jLogger := jaeger.StdLogger
-	jMetricsFactory := metrics.NullFactory
-
-	tracer, closer, err := cfg.NewTracer(
-		jaegercfg.Logger(jLogger),
-		jaegercfg.Metrics(jMetricsFactory),
-	)
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot initialize jaeger tracer for agent service: %v", err)
-	}
-
-	// Set as global tracer
-	opentracing.SetGlobalTracer(tracer)
-
-	return tracer, func() { closer.Close() }, nil
-}
-
-func initLogger() *log.Logger {
+func initLogger(logFile string) *log.Logger {
 	// First create stdout logger for debugging
 	bootstrapLog := log.New(os.Stdout, "[INIT] ", log.LstdFlags|log.Lshortfile)
 	bootstrapLog.Println("Logger initialization started")
 
-	logDir := "/var/log"
-	logFile := filepath.Join(logDir, "agent.log")
+	if logFile == config.LogStdout {
+		bootstrapLog.Println("Logging to stdout")
+		return log.New(os.Stdout, "", log.LstdFlags|log.Lshortfile)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		bootstrapLog.Fatalf("Failed to create log directory: %v", err)
+	}
 
 	file, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
@@ -276,107 +75,227 @@ func initLogger() *log.Logger {
 }
 
 func main() {
-	logger := initLogger()
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	logger := initLogger(cfg.Log.Path)
 	defer func() {
-		if f, ok := logger.Writer().(*os.File); ok {
+		if f, ok := logger.Writer().(*os.File); ok && f != os.Stdout {
 			f.Close()
 		}
 	}()
 
 	logger.Println("MAIN STARTED")
+	logger.Printf("Config - Interval: %s", cfg.Interval)
 
-	metricsPort := os.Getenv("METRICS_PORT")
-	if metricsPort == "" {
-		metricsPort = "2112"
+	startMetricServer(strconv.Itoa(cfg.MetricsPort))
+
+	// init tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatalf("Failed to initialize tracing: %v", err)
 	}
+	defer shutdownTracing(context.Background())
+	tracer := otel.Tracer("gomon/agent")
 
-	startMetricServer(metricsPort)
+	kafkaBrokers := strings.Join(cfg.Kafka.Brokers, ",")
+	kafkaTopic := cfg.Kafka.Topic
 
-	// init jaeger
-	tracer, closer, err := initJaeger()
-	if err != nil {
-		logger.Fatalf("Failed to initialize Jaeger tracer: %v", err)
+	logger.Printf("Kafka config - Brokers: %s, Topic: %s", kafkaBrokers, kafkaTopic)
+
+	var sp *spool.Spool
+	if cfg.Spool.Enabled {
+		sp, err = spool.Open(cfg.Spool.Dir, cfg.Spool.MaxBytes, cfg.Spool.MaxAge)
+		if err != nil {
+			logger.Fatalf("Failed to open spool: %v", err)
+		}
+		logger.Printf("Spool config - Dir: %s, MaxBytes: %d, MaxAge: %s", cfg.Spool.Dir, cfg.Spool.MaxBytes, cfg.Spool.MaxAge)
 	}
-	defer closer()
 
-	// Read Kafka env variables
-	kafkaBrokers, err := kafka.GetKafkaBrokers()
+	// Resolved before the producer, the host name keys its messages
+	hostname, hostIdentity := collector.ResolveIdentity(cfg)
+	logger.Printf("Host identity - Hostname: %s, MachineID: %s, Node: %s, Cloud: %s %s", hostname,
+		hostIdentity.MachineId, hostIdentity.NodeName, hostIdentity.CloudProvider, hostIdentity.CloudInstanceId)
+
+	producer, err := kafka.NewKafkaProducerWithConfig(kafkaBrokers, kafkaTopic, producerConfig(logger, cfg.Kafka, hostname, sp))
 	if err != nil {
-		logger.Fatal(err)
+		logger.Fatalf("Failed to create Kafka producer: %v", err)
 	}
-	kafkaTopic, err := kafka.GetKafkaTopic()
-	if err != nil {
-		logger.Fatal(err)
+	defer producer.Close()
+
+	// A replayed payload leaves the spool once sent, so the replay needs to know
+	// it was delivered: in async mode it gets a synchronous writer of its own
+	replayProducer := producer
+	if sp != nil && cfg.Kafka.Async {
+		replayCfg := cfg.Kafka
+		replayCfg.Async = false
+		replayProducer, err = kafka.NewKafkaProducerWithConfig(kafkaBrokers, kafkaTopic, producerConfig(logger, replayCfg, hostname, nil))
+		if err != nil {
+			logger.Fatalf("Failed to create Kafka replay producer: %v", err)
+		}
+		defer replayProducer.Close()
 	}
 
-	logger.Printf("Kafka config - Brokers: %s, Topic: %s", kafkaBrokers, kafkaTopic)
+	registry, err := collector.NewRegistry(cfg)
+	if err != nil {
+		logger.Fatalf("Failed to initialize collectors: %v", err)
+	}
+	logger.Printf("Enabled collectors: %s", strings.Join(registry.Enabled(), ", "))
 
-	producer := kafka.NewKafkaProducer(kafkaBrokers, kafkaTopic)
-	defer producer.Close()
+	// Discovered once, a failed lookup only costs the labels it would have added
+	hostLabels, err := collector.ResolveLabels(context.Background(), cfg, hostIdentity)
+	if err != nil {
+		logger.Printf("WARNING: Host label discovery incomplete: %v", err)
+	}
+	logger.Printf("Host labels: %v", hostLabels)
 
 	i := 0
-	sleepInSeconds := 20
 	for {
 
 		//Generate CorrelationID
 		correlationID := generateCorrelationID()
 
-		rootSpan := tracer.StartSpan("gomon-metrics-collection")
-		rootSpan.SetTag("correlation_id", correlationID)
-		rootSpan.SetTag("iteration", i+1)
+		ctx, rootSpan := tracer.Start(context.Background(), "gomon-metrics-collection")
+		rootSpan.SetAttributes(
+			attribute.String("correlation_id", correlationID),
+			attribute.Int("iteration", i+1),
+		)
 
 		// Time to start sending metric
 		traceStartTime := time.Now().UTC()
 
 		metric := &pb.Metric{
+			Hostname:       hostname,
+			Host:           hostIdentity,
+			Labels:         hostLabels,
 			Timestamp:      strconv.FormatInt(time.Now().Unix(), 10),
 			CorrelationId:  correlationID,
 			TraceStartTime: traceStartTime.Format(time.RFC3339Nano),
 		}
 		i++
 
-		var wg sync.WaitGroup
-		wg.Add(4)
-		go collectCPU(&wg, metric, rootSpan)
-		go collectMemory(&wg, metric, rootSpan)
-		go collectDisk(&wg, metric, rootSpan)
-		go collectNet(&wg, metric, rootSpan)
-		wg.Wait()
+		registry.Collect(ctx, metric)
+
+		// Stamped before marshalling so the aggregator can measure the Kafka leg
+		publishCtx, kafkaSpan := tracer.Start(ctx, "kafka-publish")
+		kafkaPublishStart := time.Now().UTC()
+		metric.KafkaPublishTime = kafkaPublishStart.Format(time.RFC3339Nano)
 
-		data, err := proto.Marshal(metric)
+		data, err := schema.Encode(metric, cfg.Kafka.SchemaVersion)
 		if err != nil {
 			logger.Printf("ERROR: Failed to marshal metric (Iteration %d): %v", i, err)
-			rootSpan.SetTag("error", true)
-			rootSpan.Finish()
+			kafkaSpan.End()
+			rootSpan.SetStatus(codes.Error, "marshal failed")
+			rootSpan.End()
 			continue
 		}
 
 		// Log the actual metric data being sent
 		logger.Printf("Sending to Kafka (Iteration %d):\n%s", i, formatMetricForLog(metric))
 
-		kafkaSpan := opentracing.StartSpan("kafka-publish", opentracing.ChildOf(rootSpan.Context()))
-		kafkaPublishStart := time.Now().UTC()
-		metric.KafkaPublishTime = kafkaPublishStart.Format(time.RFC3339Nano)
-
-		if err := producer.SendMessage(data); err != nil {
+		if err := publish(publishCtx, logger, producer, replayProducer, sp, data, cfg.Kafka.WriteTimeout); err != nil {
 			logger.Printf("ERROR: Failed to send message (Iteration %d): %v", i, err)
-			kafkaSpan.SetTag("error", true)
-			kafkaSpan.Finish()
-			rootSpan.SetTag("error", true)
+			kafkaSpan.RecordError(err)
+			kafkaSpan.SetStatus(codes.Error, "publish failed")
+			kafkaSpan.End()
+			rootSpan.SetStatus(codes.Error, "publish failed")
 		} else {
 			kafkaLatency := time.Since(kafkaPublishStart)
 			logger.Printf("Agent vs Kafka publish latency: %v (CorrelationID: %s)",
 				kafkaLatency, correlationID)
-			kafkaSpan.SetTag("latency_ms", kafkaLatency.Milliseconds())
-			kafkaSpan.SetTag("success", true)
-			kafkaSpan.Finish()
+			kafkaSpan.SetAttributes(attribute.Int64("latency_ms", kafkaLatency.Milliseconds()))
+			kafkaSpan.SetStatus(codes.Ok, "")
+			kafkaSpan.End()
 		}
 
-		rootSpan.Finish()
+		rootSpan.End()
+
+		logger.Printf("INFO: Cycle completed (Iteration %d, Sleep: %s)", i, cfg.Interval)
+		time.Sleep(cfg.Interval)
+	}
+}
+
+// producerConfig maps the agent config to producer settings. Messages are keyed by
+// the resolved host name: in a DaemonSet os.Hostname is the pod, not the node.
+// In async mode SendMessage cannot report failures, so failed batches are spooled
+// here, in the place their collection time gives them rather than at the tail.
+func producerConfig(logger *log.Logger, kafkaCfg config.KafkaConfig, hostname string, sp *spool.Spool) kafka.ProducerConfig {
+	producerCfg := kafka.ProducerConfig{
+		BatchSize:   kafkaCfg.BatchSize,
+		BatchBytes:  kafkaCfg.BatchBytes,
+		Linger:      kafkaCfg.Linger,
+		Compression: kafkaCfg.Compression,
+		Acks:        kafkaCfg.Acks,
+		// The first attempt plus the retries
+		MaxAttempts:  kafkaCfg.Retries + 1,
+		WriteTimeout: kafkaCfg.WriteTimeout,
+		Async:        kafkaCfg.Async,
+		Key:          hostname,
+		Security:     kafkaCfg.Security,
+	}
+	if !kafkaCfg.Async {
+		return producerCfg
+	}
 
-		logger.Printf("INFO: Cycle completed (Iteration %d, Sleep: %ds)", i, sleepInSeconds)
-		time.Sleep(time.Duration(sleepInSeconds) * time.Second)
+	producerCfg.OnDelivery = func(messages []kafkago.Message, err error) {
+		if err == nil {
+			return
+		}
+		logger.Printf("ERROR: Kafka delivery failed for %d messages: %v", len(messages), err)
+		if sp == nil {
+			return
+		}
+		for _, msg := range messages {
+			if err := sp.Insert(msg.Value, msg.Time); err != nil {
+				logger.Printf("ERROR: Failed to spool payload, dropping it: %v", err)
+			}
+		}
 	}
+	return producerCfg
+}
+
+// publish sends data to Kafka. While payloads are spooled, data is spooled behind
+// them first so order is kept and it is never lost to a slow replay, then the
+// spool is replayed through replayProducer for at most replayTimeout, so an
+// unavailable Kafka does not hold up the collection loop. The send error is
+// returned while data is spooled. The span in ctx is propagated in the message
+// headers; replayed payloads carry none.
+func publish(ctx context.Context, logger *log.Logger, producer, replayProducer *kafka.KafkaProducer, sp *spool.Spool, data []byte, replayTimeout time.Duration) error {
+	if sp == nil {
+		return producer.SendMessageWithContext(ctx, data)
+	}
+
+	if sp.Len() == 0 {
+		err := producer.SendMessageWithContext(ctx, data)
+		if err == nil {
+			return nil
+		}
+		spoolPayload(logger, sp, data)
+		return err
+	}
+
+	spoolPayload(logger, sp, data)
+	replayCtx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+	defer cancel()
+	replayed, err := sp.Replay(replayCtx, func(ctx context.Context, payload []byte) error {
+		return replayProducer.SendMessageWithContext(ctx, payload)
+	})
+	if replayed > 0 {
+		logger.Printf("Replayed %d spooled payloads, %d left", replayed, sp.Len())
+	}
+	return err
+}
+
+// spoolPayload keeps data for a later replay, dropping it when the spool refuses it
+func spoolPayload(logger *log.Logger, sp *spool.Spool, data []byte) {
+	if err := sp.Append(data); err != nil {
+		logger.Printf("ERROR: Failed to spool payload, dropping it: %v", err)
+		return
+	}
+	logger.Printf("Spooled payload (%d pending, %d bytes)", sp.Len(), sp.Bytes())
 }
 
 // Helper function to format metric for logging
@@ -392,26 +311,27 @@ func formatMetricForLog(m *pb.Metric) string {
 		hostname, m.Timestamp))
 
 	// CPU
-	builder.WriteString(fmt.Sprintf("CPU: %.2f%%\n", m.CpuUsagePercent))
+	builder.WriteString(fmt.Sprintf("CPU: %.2f%% (cores: %d, load: %.2f %.2f %.2f)\n",
+		m.CpuUsagePercent, len(m.GetCpuStats().GetCores()),
+		m.GetCpuStats().GetLoad1(), m.GetCpuStats().GetLoad5(), m.GetCpuStats().GetLoad15()))
 
-	// Memory - convert GB to more readable format
+	// Memory - convert bytes to more readable format
+	memStats := m.GetMemoryStats()
 	builder.WriteString(fmt.Sprintf(
-		"Memory: %.2f%% used (%.2fGB/%.2fGB free)\n",
-		m.MemoryUsedPercent,
-		float64(m.MemoryUsedGb),
-		float64(m.MemoryTotalGb),
+		"Memory: %.2f%% used (%.2fGB/%.2fGB, %.2fGB available), Swap: %.2fGB/%.2fGB\n",
+		memStats.GetUsedPercent(),
+		float64(memStats.GetUsedBytes())/(1<<30),
+		float64(memStats.GetTotalBytes())/(1<<30),
+		float64(memStats.GetAvailableBytes())/(1<<30),
+		float64(memStats.GetSwapUsedBytes())/(1<<30),
+		float64(memStats.GetSwapTotalBytes())/(1<<30),
 	))
 
-	// Disk - filter out system mounts and format sizes properly
+	// Disk - system mounts are already filtered at collection time
 	if len(m.DiskStats) > 0 {
 		builder.WriteString("Disks:\n")
 		for _, disk := range m.DiskStats {
 			mountPoint := disk.GetMountpoint()
-			// Skip system mounts in logs
-			if strings.HasPrefix(mountPoint, "/etc/") ||
-				strings.HasPrefix(mountPoint, "/dev/") {
-				continue
-			}
 
 			// Convert bytes to GB (assuming the proto uses bytes)
 			totalGB := float64(disk.GetTotalGb()) / 1024 / 1024 / 1024
@@ -427,6 +347,26 @@ func formatMetricForLog(m *pb.Metric) string {
 		}
 	}
 
+	// Disk I/O - only once rates are available
+	if len(m.DiskIoStats) > 0 {
+		builder.WriteString("Disk I/O:\n")
+		for _, io := range m.DiskIoStats {
+			if io.GetRateIntervalSeconds() == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf(
+				"  %s: Read %.2fKB/s (%.1f IOPS), Write %.2fKB/s (%.1f IOPS), Await %.2fms, Util %.2f%%\n",
+				io.GetDevice(),
+				io.GetReadBytesPerSec()/1024,
+				io.GetReadIops(),
+				io.GetWriteBytesPerSec()/1024,
+				io.GetWriteIops(),
+				io.GetAvgAwaitMs(),
+				io.GetUtilizationPercent(),
+			))
+		}
+	}
+
 	// Network - filter aggregate interfaces
 	if len(m.NetStats) > 0 {
 		builder.WriteString("Network:\n")
@@ -436,10 +376,60 @@ func formatMetricForLog(m *pb.Metric) string {
 				continue
 			}
 			builder.WriteString(fmt.Sprintf(
-				"  %s: Tx %.2fMB, Rx %.2fMB\n",
+				"  %s: Tx %.2fKB/s, Rx %.2fKB/s (total Tx %.2fMB, Rx %.2fMB)\n",
 				ifName,
-				float64(net.GetBytesReceived()),
-				float64(net.GetBytesSent()),
+				net.GetBytesSentPerSec()/1024,
+				net.GetBytesReceivedPerSec()/1024,
+				float64(net.GetBytesSent())/1024/1024,
+				float64(net.GetBytesReceived())/1024/1024,
+			))
+		}
+	}
+
+	// Top processes
+	if len(m.ProcessStats) > 0 {
+		builder.WriteString("Processes:\n")
+		for _, proc := range m.ProcessStats {
+			builder.WriteString(fmt.Sprintf(
+				"  %s (pid %d): CPU %.2f%%, RSS %.2fMB, FDs %d, Threads %d\n",
+				proc.GetName(),
+				proc.GetPid(),
+				proc.GetCpuPercent(),
+				float64(proc.GetRssBytes())/1024/1024,
+				proc.GetOpenFds(),
+				proc.GetThreads(),
+			))
+		}
+	}
+
+	// Containers - only once rates are available
+	if len(m.ContainerStats) > 0 {
+		builder.WriteString("Containers:\n")
+		for _, container := range m.ContainerStats {
+			if container.GetRateIntervalSeconds() == 0 {
+				continue
+			}
+			builder.WriteString(fmt.Sprintf(
+				"  %.12s: CPU %.2f%%, Memory %.2fMB, OOM kills %d\n",
+				container.GetContainerId(),
+				container.GetCpuUsagePercent(),
+				float64(container.GetMemoryWorkingSetBytes())/1024/1024,
+				container.GetOomKills(),
+			))
+		}
+	}
+
+	// Pressure stall information
+	if len(m.PressureStats) > 0 {
+		builder.WriteString("Pressure:\n")
+		for _, psi := range m.PressureStats {
+			builder.WriteString(fmt.Sprintf(
+				"  %s %s: avg10 %.2f%%, avg60 %.2f%%, avg300 %.2f%%\n",
+				psi.GetResource(),
+				psi.GetKind(),
+				psi.GetAvg10(),
+				psi.GetAvg60(),
+				psi.GetAvg300(),
 			))
 		}
 	}
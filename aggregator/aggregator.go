@@ -1,54 +1,48 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"gomon/pb"
-	"io"
+	gomonkafka "gomon/kafka"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/uber/jaeger-client-go"
-	jaegercfg "github.com/uber/jaeger-client-go/config"
-	"github.com/uber/jaeger-lib/metrics"
+	"gomon/schema"
+	"gomon/tracing"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const numberOfWorkers = 4
 
-var victoriaMetricsURL string
-
-var httpClient = &http.Client{
-	Timeout: 10 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 100,
-		IdleConnTimeout:     90 * time.Second,
-	},
-}
+// labelGuard applies the AGGREGATOR_LABELS_* policy to every series
+var labelGuard = NewLabelGuard(DefaultLabelPolicy())
 
 type Job struct {
-	data         []byte
+	msg          kafka.Message
 	kafkaRecTime time.Time
 }
 
-// Expose metrics for VM
+// Expose metrics for VM. OpenMetrics is negotiated so scrapers can read the latency exemplars.
 func startMetricServer(port string) {
-	http.Handle("/metrics", promhttp.Handler())
+	http.Handle("/metrics", promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
 
 	go func() {
 		log.Printf("Starting metrics server on :%s", port)
@@ -68,36 +62,17 @@ func livenessProbeCheck() {
 	})
 }
 
-// Jaeger
-func initJaeger() (opentracing.Tracer, func(), error) {
-	cfg := jaegercfg.Configuration{
-		ServiceName: "gomon-aggregator",
-		Sampler: &jaegercfg.SamplerConfig{
-			Type:  jaeger.SamplerTypeConst,
-			Param: 1, // Sample 100% of traces for development
-		},
-		Reporter: &jaegercfg.ReporterConfig{
-			LogSpans:          true,                             // Enable span logging for debugging
-			CollectorEndpoint: "http://jaeger:14268/api/traces", // Jaeger agent HTTP endpoint
-		},
-	}
-
-	jLogger := jaeger.StdLogger
-	jMetricsFactory := metrics.NullFactory
-
-	tracer, closer, err := cfg.NewTracer(
-		jaegercfg.Logger(jLogger),
-		jaegercfg.Metrics(jMetricsFactory),
-	)
-
-	if err != nil {
-		return nil, nil, fmt.Errorf("cannot initialize jaeger tracer for aggregator service: %v", err)
-	}
-
-	// Set as global tracer
-	opentracing.SetGlobalTracer(tracer)
-
-	return tracer, func() { closer.Close() }, nil
+// Sink health, 503 while any sink keeps failing
+func sinkHealthCheck(sink Sink) {
+	http.HandleFunc("/health/sinks", func(w http.ResponseWriter, r *http.Request) {
+		if err := sink.Health(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.WriteHeader(200)
+		w.Write([]byte("ok"))
+	})
 }
 
 func initLogger() *log.Logger {
@@ -120,13 +95,18 @@ func initLogger() *log.Logger {
 	return log.New(file, "", log.LstdFlags|log.Lshortfile)
 }
 
-func StartAggregator(logger *log.Logger) error {
-	// init jaeger
-	tracer, closer, err := initJaeger()
+func StartAggregator(logger *log.Logger, sink Sink) error {
+	// init tracing, TRACING_* variables override the defaults
+	tracingCfg, err := tracing.GetConfig("gomon-aggregator")
+	if err != nil {
+		logger.Fatalf("Invalid tracing config: %v", err)
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracingCfg)
 	if err != nil {
-		logger.Fatalf("Failed to initialize Jaeger tracer: %v", err)
+		logger.Fatalf("Failed to initialize tracing: %v", err)
 	}
-	defer closer()
+	defer shutdownTracing(context.Background())
+	tracer := otel.Tracer("gomon/aggregator")
 
 	// Read Kafka env variable
 	kafkaBrokers := os.Getenv("KAFKA_BROKERS")
@@ -140,14 +120,31 @@ func StartAggregator(logger *log.Logger) error {
 		logger.Fatal("KAFKA_TOPIC environment variable is not set")
 	}
 
+	// TLS and SASL from KAFKA_TLS_* and KAFKA_SASL_*, plaintext when unset
+	security, err := gomonkafka.GetSecurityConfig()
+	if err != nil {
+		logger.Fatalf("Invalid Kafka security settings: %v", err)
+	}
+	dialer, err := security.Dialer()
+	if err != nil {
+		logger.Fatalf("Failed to build Kafka dialer: %v", err)
+	}
+
 	log.Printf("Creating Kafka producer with brokers %v and topic %s", kafkaBrokers, kafkaTopic)
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: strings.Split(kafkaBrokers, ","),
 		GroupID: "metrics-group",
 		Topic:   kafkaTopic,
+		Dialer:  dialer,
 	})
 	defer reader.Close()
 
+	// Payloads that fail go to AGGREGATOR_DLQ_TOPIC, when set, instead of being lost
+	dlq, err := NewDeadLetterQueue(kafkaBrokers, security, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create dead-letter queue: %v", err)
+	}
+
 	// buffered channel
 	jobs := make(chan Job, 10*numberOfWorkers)
 
@@ -161,7 +158,7 @@ func StartAggregator(logger *log.Logger) error {
 	// Start workers
 	for i := 0; i < numberOfWorkers; i++ {
 		wg.Add(1)
-		go worker(ctx, i, jobs, logger, tracer, &wg)
+		go worker(ctx, i, jobs, logger, tracer, sink, dlq, &wg)
 	}
 
 	sigs := make(chan os.Signal, 1)
@@ -185,7 +182,7 @@ func StartAggregator(logger *log.Logger) error {
 
 				// Dispatch to worker pool
 				jobs <- Job{
-					data:         msg.Value,
+					msg:          msg,
 					kafkaRecTime: kafkaReceiveStart,
 				}
 			}
@@ -197,185 +194,130 @@ func StartAggregator(logger *log.Logger) error {
 	logger.Println("Received termination signal, stopping aggregator...")
 
 	// Graceful shutdown
-	cancel()    // Signal all goroutines to stop
-	close(jobs) // Close job channel
-	wg.Wait()   // Wait for workers to finish
+	cancel()     // Signal all goroutines to stop
+	close(jobs)  // Close job channel
+	wg.Wait()    // Wait for workers to finish
+	sink.Close() // Flush what the sinks still hold
+	dlq.Close()  // After the sinks, their failures are dead-lettered
 
 	logger.Println("All workers stopped, aggregator shutdown complete")
 	return nil
 
 }
 
-// processAndSendMetrics processes and sends separate metrics to VictoriaMetrics
-func processAndSendMetrics(protoData []byte, logger *log.Logger, kafkaReceiveStart time.Time, tracer opentracing.Tracer) error {
+// processAndSendMetrics turns a payload into series and queues them on the sink.
+// Payloads that cannot be decoded or written go to the dead-letter queue.
+func processAndSendMetrics(msg kafka.Message, logger *log.Logger, kafkaReceiveStart time.Time, tracer trace.Tracer, sink Sink, dlq *DeadLetterQueue) error {
+	protoData := msg.Value
 
-	// Create NEW root span for aggregator
-	aggregatorRootSpan := tracer.StartSpan("gomon-aggregator-processing")
-	defer aggregatorRootSpan.Finish()
+	// Continue the agent's trace when it sent one, older agents start a new root span
+	ctx := gomonkafka.ExtractContext(context.Background(), msg.Headers)
+	ctx, aggregatorRootSpan := tracer.Start(ctx, "gomon-aggregator-processing", trace.WithSpanKind(trace.SpanKindConsumer))
 
 	// SPAN 1: kafka-consume (includes unmarshalling)
-	kafkaSpan := opentracing.StartSpan("kafka-consume", opentracing.ChildOf(aggregatorRootSpan.Context()))
+	_, kafkaSpan := tracer.Start(ctx, "kafka-consume")
 
-	var metric pb.Metric
-	err := proto.Unmarshal(protoData, &metric)
+	// v1 and v2 payloads are both read into a v2 batch
+	batch, version, err := schema.Decode(protoData)
 	if err != nil {
-		kafkaSpan.SetTag("error", true)
-		kafkaSpan.Finish()
-		aggregatorRootSpan.SetTag("error", true)
+		kafkaSpan.RecordError(err)
+		kafkaSpan.SetStatus(codes.Error, "unmarshal failed")
+		kafkaSpan.End()
+		aggregatorRootSpan.SetStatus(codes.Error, "unmarshal failed")
+		aggregatorRootSpan.End()
+		dlq.Send(msg, StageDecode, err)
 		return fmt.Errorf("could not unmarshal protobuf data: %v", err)
 	}
+	payloadsBySchema.WithLabelValues(strconv.FormatUint(uint64(version), 10)).Inc()
 
-	correlationID := metric.CorrelationId
-	aggregatorRootSpan.SetTag("correlation_id", correlationID)
+	correlationID := batch.CorrelationId
+	batch.AggregatorReceivedTimeMs = kafkaReceiveStart.UnixMilli()
+	aggregatorRootSpan.SetAttributes(
+		attribute.String("correlation_id", correlationID),
+		attribute.Int("schema_version", int(version)),
+	)
 
 	kafkaLatency := time.Since(kafkaReceiveStart)
-	kafkaSpan.SetTag("kafka_latency_ms", kafkaLatency.Milliseconds())
-	kafkaSpan.SetTag("success", true)
-	kafkaSpan.Finish()
+	kafkaSpan.SetAttributes(attribute.Int64("kafka_latency_ms", kafkaLatency.Milliseconds()))
+	kafkaSpan.SetStatus(codes.Ok, "")
+	kafkaSpan.End()
 
-	logger.Printf("Kafka vs Aggregator latency: %v (CorrelationID: %s)", kafkaLatency, correlationID)
-
-	// SPAN 2: process-metrics (prepare all metric data)
-	processSpan := opentracing.StartSpan("process-metrics", opentracing.ChildOf(aggregatorRootSpan.Context()))
-
-	// Prepare all metrics data for VictoriaMetrics
-	var metricsData []map[string]interface{}
-
-	// Get hostname once
-	hostname, err := os.Hostname()
-	if err != nil {
-		processSpan.SetTag("error", true)
-		processSpan.Finish()
-		return fmt.Errorf("error getting hostname: %v", err)
+	traceID := ""
+	if sc := aggregatorRootSpan.SpanContext(); sc.HasTraceID() {
+		traceID = sc.TraceID().String()
 	}
+	logger.Printf("Kafka vs Aggregator latency: %v (CorrelationID: %s, TraceID: %s)", kafkaLatency, correlationID, traceID)
 
-	// Process individual metrics
-	metricsProcessed := 0
-
-	// CPU metric
-	if metric.CpuUsagePercent > 0 {
-		data := CreateMetricData("cpu_usage_percent", float64(metric.CpuUsagePercent), metric.Timestamp, correlationID, hostname)
-		metricsData = append(metricsData, data)
-		metricsProcessed++
-	}
+	// SPAN 2: process-metrics (prepare all metric data)
+	_, processSpan := tracer.Start(ctx, "process-metrics")
 
-	// Memory metric
-	if metric.MemoryUsedPercent > 0 {
-		data := CreateMetricData("mem_usage_percent", float64(metric.MemoryUsedPercent), metric.Timestamp, correlationID, hostname)
-		metricsData = append(metricsData, data)
-		metricsProcessed++
-	}
+	// Series are labelled with the host the agent reported for
+	instance := instanceLabel(batch.Hostname, batch.Host)
+	processSpan.SetAttributes(attribute.String("instance", instance))
 
-	// Disk used GB metric
-	if metric.MemoryUsedGb > 0 {
-		data := CreateMetricData("dsk_used_gb", float64(metric.MemoryUsedGb), metric.Timestamp, correlationID, hostname)
-		metricsData = append(metricsData, data)
-		metricsProcessed++
-	}
+	// Prepare all metrics data for the sinks
+	metricsData := labelGuard.Apply(buildSampleData(batch, instance), logger)
+	metricsProcessed := len(metricsData)
 
-	// Disk stats
-	for _, disk := range metric.DiskStats {
-		if disk != nil {
-			data := CreateMetricData("disk_used_percent", float64(disk.UsedPercent), metric.Timestamp, correlationID, hostname)
-			metricsData = append(metricsData, data)
-			metricsProcessed++
-		}
-	}
+	processSpan.SetAttributes(attribute.Int("metrics_processed", metricsProcessed))
+	processSpan.SetStatus(codes.Ok, "")
+	processSpan.End()
 
-	// Network stats
-	for _, net := range metric.NetStats {
-		if net != nil {
-			// Bytes received
-			data1 := CreateMetricData("int_bytes_recv_mb", float64(net.BytesReceived>>20), metric.Timestamp, correlationID, hostname)
-			metricsData = append(metricsData, data1)
-
-			// Bytes sent
-			data2 := CreateMetricData("int_bytes_sent_mb", float64(net.BytesSent>>20), metric.Timestamp, correlationID, hostname)
-			metricsData = append(metricsData, data2)
-			metricsProcessed += 2
+	// A replayed payload only goes to the sinks it failed on, the others have it
+	if names := deadLetterSinks(msg.Headers); len(names) > 0 {
+		if fanOut, ok := sink.(*FanOut); ok {
+			sink = fanOut.Only(names)
 		}
 	}
 
-	processSpan.SetTag("metrics_processed", metricsProcessed)
-	processSpan.SetTag("success", true)
-	processSpan.Finish()
+	// SPAN 3: sink-write, ends when every sink wrote the batch holding these series
+	_, sinkSpan := tracer.Start(ctx, "sink-write", trace.WithSpanKind(trace.SpanKindClient))
+	sinkSpan.SetAttributes(attribute.Int("series", metricsProcessed), attribute.String("sink", sink.Name()))
 
-	// SPAN 3: victoria-metrics-publish (all HTTP calls)
-	vmSpan := opentracing.StartSpan("victoria-metrics-publish", opentracing.ChildOf(aggregatorRootSpan.Context()))
+	payload := &Payload{Raw: protoData, Version: version, Batch: batch, TraceID: traceID, Series: metricsData}
+	err = sink.Write(payload, func(err error) {
+		defer aggregatorRootSpan.End()
+		defer sinkSpan.End()
 
-	successfulSends := 0
-	failedSends := 0
-
-	for _, data := range metricsData {
-		err := sendToVictoriaMetrics(data, logger)
 		if err != nil {
-			failedSends++
-			logger.Printf("Error sending metric to VictoriaMetrics: %v", err)
-		} else {
-			successfulSends++
+			sinkSpan.RecordError(err)
+			sinkSpan.SetAttributes(attribute.StringSlice("failed_sinks", failedSinks(err)))
+			sinkSpan.SetStatus(codes.Error, "sink write failed")
+			aggregatorRootSpan.SetStatus(codes.Error, "sink write failed")
+			logger.Printf("Failed to write %d metrics to %s (CorrelationID: %s, TraceID: %s): %v",
+				metricsProcessed, sink.Name(), correlationID, traceID, err)
+			dlq.Send(msg, StageWrite, err)
+			return
 		}
-	}
-
-	vmSpan.SetTag("successful_sends", successfulSends)
-	vmSpan.SetTag("failed_sends", failedSends)
-
-	if failedSends > 0 {
-		vmSpan.SetTag("error", true)
-		aggregatorRootSpan.SetTag("error", true)
-	} else {
-		vmSpan.SetTag("success", true)
-	}
-
-	vmSpan.Finish()
-
-	if failedSends > 0 {
-		return fmt.Errorf("failed to send %d out of %d metrics to VictoriaMetrics", failedSends, len(metricsData))
-	}
-
-	logger.Printf("Successfully processed and sent %d metrics to VictoriaMetrics", successfulSends)
-	return nil
-}
-
-// sendToVictoriaMetrics sends data to VictoriaMetrics
-func sendToVictoriaMetrics(data map[string]interface{}, logger *log.Logger) error {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("could not marshal JSON: %v", err)
-	}
+		sinkSpan.SetStatus(codes.Ok, "")
 
-	req, err := http.NewRequest("POST", victoriaMetricsURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("could not create HTTP request: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := httpClient.Do(req)
+		batch.VmPublishTimeMs = time.Now().UnixMilli()
+		observePipelineLatency(batch, traceID)
+		logger.Printf("Successfully processed and sent %d metrics to %s (CorrelationID: %s, TraceID: %s)",
+			metricsProcessed, sink.Name(), correlationID, traceID)
+	})
 	if err != nil {
-		return fmt.Errorf("could not send HTTP request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	// Log the JSON for debugging
-	logger.Printf("Sending JSON to VictoriaMetrics: %s", string(jsonData))
-	logger.Printf("Response Status: %d", resp.StatusCode)
-	logger.Printf("Response Body: %s", string(body))
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("unexpected response from VictoriaMetrics: %s", resp.Status)
+		sinkSpan.RecordError(err)
+		sinkSpan.SetStatus(codes.Error, "sink write failed")
+		sinkSpan.End()
+		aggregatorRootSpan.SetStatus(codes.Error, "sink write failed")
+		aggregatorRootSpan.End()
+		dlq.Send(msg, StageQueue, err)
+		return fmt.Errorf("could not queue %d metrics for %s: %v", metricsProcessed, sink.Name(), err)
 	}
-
-	logger.Printf("Successfully sent metrics to VictoriaMetrics. Status: %s", resp.Status)
 	return nil
 }
 
 func main() {
-	victoriaMetricsURL = os.Getenv("VICTORIA_METRICS_URL")
-	if victoriaMetricsURL == "" {
-		log.Fatal("VICTORIA_METRICS_URL environment variable is not set")
+	// aggregator replay-dlq [-limit n] [-idle d] [-max-attempts n]
+	if len(os.Args) > 1 && os.Args[1] == "replay-dlq" {
+		logger := log.New(os.Stdout, "[REPLAY] ", log.LstdFlags)
+		if err := runReplay(os.Args[2:], logger); err != nil {
+			logger.Fatalf("Replay failed: %v", err)
+		}
+		return
 	}
+
 	logger := initLogger()
 	defer func() {
 		if f, ok := logger.Writer().(*os.File); ok {
@@ -385,6 +327,17 @@ func main() {
 
 	logger.Println("AGGREGATOR MAIN STARTED")
 
+	policy, err := GetLabelPolicy()
+	if err != nil {
+		logger.Fatalf("Invalid label policy: %v", err)
+	}
+	labelGuard = NewLabelGuard(policy)
+
+	sinks, err := NewSinks(logger)
+	if err != nil {
+		logger.Fatalf("Invalid sink settings: %v", err)
+	}
+
 	metricsPort := os.Getenv("METRICS_PORT")
 	if metricsPort == "" {
 		metricsPort = "2113"
@@ -394,14 +347,15 @@ func main() {
 	//liveness endpoint
 	logger.Println("Acivate Liveness Probe")
 	livenessProbeCheck()
+	sinkHealthCheck(sinks)
 
-	err := StartAggregator(logger)
+	err = StartAggregator(logger, sinks)
 	if err != nil {
 		logger.Fatal("Failed to start aggregator:", err)
 	}
 }
 
-func worker(ctx context.Context, id int, jobs <-chan Job, logger *log.Logger, tracer opentracing.Tracer, wg *sync.WaitGroup) {
+func worker(ctx context.Context, id int, jobs <-chan Job, logger *log.Logger, tracer trace.Tracer, sink Sink, dlq *DeadLetterQueue, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	logger.Printf("Worker %d started", id)
@@ -418,7 +372,7 @@ func worker(ctx context.Context, id int, jobs <-chan Job, logger *log.Logger, tr
 				return
 			}
 
-			err := processAndSendMetrics(job.data, logger, job.kafkaRecTime, tracer)
+			err := processAndSendMetrics(job.msg, logger, job.kafkaRecTime, tracer, sink, dlq)
 			if err != nil {
 				logger.Printf("Worker %d: error processing message: %v", id, err)
 			}
@@ -36,7 +36,6 @@ func TestKafkaDeserialization(t *testing.T) {
 
 // VictoriaMetrics serialization (Go struct → VM format)
 func TestVMSerialization(t *testing.T) {
-	correlationID := testutils.GetCorrelationID()
 	hostname, err := testutils.GetHostname()
 	if err != nil {
 		t.Error("Error while getting hostname!")
@@ -46,21 +45,21 @@ func TestVMSerialization(t *testing.T) {
 	metric := testutils.CreateMetric()
 
 	if metric.CpuUsagePercent > 0 {
-		data := CreateMetricData("cpu_usage_percent", float64(metric.CpuUsagePercent), metric.Timestamp, correlationID, hostname)
+		data := CreateMetricData("cpu_usage_percent", float64(metric.CpuUsagePercent), metric.Timestamp, hostname)
 		metricsData = append(metricsData, data)
 		metricsProcessed++
 	}
 
 	// Memory metric
 	if metric.MemoryUsedPercent > 0 {
-		data := CreateMetricData("mem_usage_percent", float64(metric.MemoryUsedPercent), metric.Timestamp, correlationID, hostname)
+		data := CreateMetricData("mem_usage_percent", float64(metric.MemoryUsedPercent), metric.Timestamp, hostname)
 		metricsData = append(metricsData, data)
 		metricsProcessed++
 	}
 
 	// Disk used GB metric
 	if metric.MemoryUsedGb > 0 {
-		data := CreateMetricData("dsk_used_gb", float64(metric.MemoryUsedGb), metric.Timestamp, correlationID, hostname)
+		data := CreateMetricData("dsk_used_gb", float64(metric.MemoryUsedGb), metric.Timestamp, hostname)
 		metricsData = append(metricsData, data)
 		metricsProcessed++
 	}
@@ -68,7 +67,7 @@ func TestVMSerialization(t *testing.T) {
 	// Disk stats
 	for _, disk := range metric.DiskStats {
 		if disk != nil {
-			data := CreateMetricData("disk_used_percent", float64(disk.UsedPercent), metric.Timestamp, correlationID, hostname)
+			data := CreateMetricData("disk_used_percent", float64(disk.UsedPercent), metric.Timestamp, hostname)
 			metricsData = append(metricsData, data)
 			metricsProcessed++
 		}
@@ -78,11 +77,11 @@ func TestVMSerialization(t *testing.T) {
 	for _, net := range metric.NetStats {
 		if net != nil {
 			// Bytes received
-			data1 := CreateMetricData("int_bytes_recv_mb", float64(net.BytesReceived>>20), metric.Timestamp, correlationID, hostname)
+			data1 := CreateMetricData("int_bytes_recv_mb", float64(net.BytesReceived>>20), metric.Timestamp, hostname)
 			metricsData = append(metricsData, data1)
 
 			// Bytes sent
-			data2 := CreateMetricData("int_bytes_sent_mb", float64(net.BytesSent>>20), metric.Timestamp, correlationID, hostname)
+			data2 := CreateMetricData("int_bytes_sent_mb", float64(net.BytesSent>>20), metric.Timestamp, hostname)
 			metricsData = append(metricsData, data2)
 			metricsProcessed += 2
 		}
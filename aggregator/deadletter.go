@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	gomonkafka "gomon/kafka"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// Stages a payload can fail in, the gomon-dlq-stage header
+const (
+	// StageDecode is a payload that is not a metrics message, replaying it fails again
+	StageDecode = "decode"
+	// StageQueue is a payload the sinks refused, e.g. while shutting down
+	StageQueue = "queue"
+	// StageWrite is a payload a sink dropped or could not write after its retries
+	StageWrite = "write"
+)
+
+// Headers added to a dead-lettered message next to its original ones
+const (
+	headerDLQError     = "gomon-dlq-error"
+	headerDLQStage     = "gomon-dlq-stage"
+	headerDLQAttempts  = "gomon-dlq-attempts"
+	headerDLQSinks     = "gomon-dlq-sinks"
+	headerDLQTopic     = "gomon-dlq-topic"
+	headerDLQPartition = "gomon-dlq-partition"
+	headerDLQOffset    = "gomon-dlq-offset"
+	headerDLQTime      = "gomon-dlq-time"
+)
+
+var deadLetters = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gomon_aggregator_dead_letters_total",
+		Help: "Payloads sent to the dead-letter topic by failed stage",
+	},
+	[]string{"stage"},
+)
+
+var deadLetterErrors = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "gomon_aggregator_dead_letter_errors_total",
+		Help: "Payloads lost because the dead-letter topic could not be written",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(deadLetters)
+	prometheus.MustRegister(deadLetterErrors)
+}
+
+// messageWriter is the part of kafka.Writer the dead-letter queue and the replay use
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// DeadLetterQueue keeps payloads that could not be processed on a Kafka topic,
+// together with why and how often they failed, until they are replayed
+type DeadLetterQueue struct {
+	topic  string
+	writer messageWriter
+	close  func()
+	logger *log.Logger
+}
+
+// NewDeadLetterQueue returns the queue for AGGREGATOR_DLQ_TOPIC, nil when it is unset.
+// Messages are written asynchronously so a slow broker does not hold up the sinks.
+func NewDeadLetterQueue(brokers string, security gomonkafka.SecurityConfig, logger *log.Logger) (*DeadLetterQueue, error) {
+	topic := os.Getenv("AGGREGATOR_DLQ_TOPIC")
+	if topic == "" {
+		return nil, nil
+	}
+
+	d := &DeadLetterQueue{topic: topic, logger: logger}
+	cfg := gomonkafka.DefaultProducerConfig()
+	cfg.Security = security
+	cfg.Async = true
+	cfg.Key = topic // unused, every message keeps its original key
+	cfg.OnDelivery = d.delivered
+	producer, err := gomonkafka.NewKafkaProducerWithConfig(brokers, topic, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create dead-letter producer: %w", err)
+	}
+	d.writer, d.close = producer.Writer, producer.Close
+	return d, nil
+}
+
+// Send dead-letters msg, which failed in stage with cause. A nil queue, with
+// dead-lettering disabled, does nothing: the caller has logged the failure.
+// The outcome is counted once the write is delivered, see delivered.
+func (d *DeadLetterQueue) Send(msg kafka.Message, stage string, cause error) {
+	if d == nil {
+		return
+	}
+
+	dead := kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: deadLetterHeaders(msg, stage, cause, time.Now()),
+	}
+	if err := d.writer.WriteMessages(context.Background(), dead); err != nil {
+		d.delivered([]kafka.Message{dead}, err)
+	}
+}
+
+// delivered counts and logs dead letters once the broker took them or they failed
+func (d *DeadLetterQueue) delivered(messages []kafka.Message, err error) {
+	for _, msg := range messages {
+		origin := fmt.Sprintf("%s/%s/%s", header(msg.Headers, headerDLQTopic),
+			header(msg.Headers, headerDLQPartition), header(msg.Headers, headerDLQOffset))
+		stage := header(msg.Headers, headerDLQStage)
+		if err != nil {
+			deadLetterErrors.Inc()
+			d.logger.Printf("Could not dead-letter message %s to %s after %s failure: %v", origin, d.topic, stage, err)
+			continue
+		}
+		deadLetters.WithLabelValues(stage).Inc()
+		d.logger.Printf("Dead-lettered message %s to %s after %s failure: %s",
+			origin, d.topic, stage, header(msg.Headers, headerDLQError))
+	}
+}
+
+// header returns the value of the first header named key
+func header(headers []kafka.Header, key string) string {
+	for _, h := range headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Close flushes the messages still being written
+func (d *DeadLetterQueue) Close() {
+	if d != nil && d.close != nil {
+		d.close()
+	}
+}
+
+// deadLetterHeaders keeps the original headers, trace context included, replaces
+// the gomon-dlq-* ones of an earlier failure and counts the attempt. The sinks
+// that failed are listed so a replay does not write to the others again.
+func deadLetterHeaders(msg kafka.Message, stage string, cause error, now time.Time) []kafka.Header {
+	attempts := deadLetterAttempts(msg.Headers) + 1
+	sinks := failedSinks(cause)
+	if len(sinks) == 0 {
+		// Failed before reaching the sinks, a replay still only owes the earlier ones
+		sinks = deadLetterSinks(msg.Headers)
+	}
+	headers := withoutDeadLetterHeaders(msg.Headers, false)
+
+	// Joined sink errors span several lines
+	reason := strings.ReplaceAll(cause.Error(), "\n", "; ")
+	for _, h := range [][2]string{
+		{headerDLQError, reason},
+		{headerDLQStage, stage},
+		{headerDLQAttempts, strconv.Itoa(attempts)},
+		{headerDLQTopic, msg.Topic},
+		{headerDLQPartition, strconv.Itoa(msg.Partition)},
+		{headerDLQOffset, strconv.FormatInt(msg.Offset, 10)},
+		{headerDLQTime, now.UTC().Format(time.RFC3339)},
+	} {
+		headers = append(headers, kafka.Header{Key: h[0], Value: []byte(h[1])})
+	}
+	if len(sinks) > 0 {
+		headers = append(headers, kafka.Header{Key: headerDLQSinks, Value: []byte(strings.Join(sinks, ","))})
+	}
+	return headers
+}
+
+// deadLetterAttempts reads how often a replayed payload failed before
+func deadLetterAttempts(headers []kafka.Header) int {
+	for _, h := range headers {
+		if h.Key == headerDLQAttempts {
+			n, _ := strconv.Atoi(string(h.Value))
+			return n
+		}
+	}
+	return 0
+}
+
+// deadLetterSinks reads the sinks a replayed payload failed on, nil for all sinks
+func deadLetterSinks(headers []kafka.Header) []string {
+	for _, h := range headers {
+		if h.Key == headerDLQSinks {
+			return splitList(string(h.Value))
+		}
+	}
+	return nil
+}
+
+// withoutDeadLetterHeaders drops the failure details. A replay keeps the attempt
+// count, so a payload that fails again is counted on, and the failed sinks, so
+// the aggregator writes it to those only.
+func withoutDeadLetterHeaders(headers []kafka.Header, replay bool) []kafka.Header {
+	var kept []kafka.Header
+	for _, h := range headers {
+		if strings.HasPrefix(h.Key, "gomon-dlq-") && !(replay && (h.Key == headerDLQAttempts || h.Key == headerDLQSinks)) {
+			continue
+		}
+		kept = append(kept, h)
+	}
+	return kept
+}
+
+// dlqReader is the part of a consumer group kafka.Reader the replay uses
+type dlqReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// ReplayOptions bound one replay run
+type ReplayOptions struct {
+	// Limit stops after this many messages, 0 replays all
+	Limit int
+	// Idle stops once no message arrived for this long, the topic is drained
+	Idle time.Duration
+	// MaxAttempts skips payloads that failed this often, 0 replays all
+	MaxAttempts int
+}
+
+// replayDeadLetters moves dead-lettered payloads back to the metrics topic. Each
+// is committed on the dead-letter topic only once it is written back, so an
+// interrupted replay resumes where it stopped. It returns the replayed and skipped counts.
+func replayDeadLetters(ctx context.Context, reader dlqReader, writer messageWriter, opts ReplayOptions, logger *log.Logger) (int, int, error) {
+	var replayed, skipped int
+	for opts.Limit == 0 || replayed+skipped < opts.Limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, opts.Idle)
+		msg, err := reader.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				logger.Printf("No dead-lettered message for %s, stopping", opts.Idle)
+				return replayed, skipped, nil
+			}
+			return replayed, skipped, fmt.Errorf("could not read dead-letter topic: %w", err)
+		}
+
+		if attempts := deadLetterAttempts(msg.Headers); opts.MaxAttempts > 0 && attempts >= opts.MaxAttempts {
+			logger.Printf("Skipping message %d/%d, it failed %d times", msg.Partition, msg.Offset, attempts)
+			skipped++
+		} else {
+			replay := kafka.Message{Key: msg.Key, Value: msg.Value, Headers: withoutDeadLetterHeaders(msg.Headers, true)}
+			if err := writer.WriteMessages(ctx, replay); err != nil {
+				return replayed, skipped, fmt.Errorf("could not replay message %d/%d: %w", msg.Partition, msg.Offset, err)
+			}
+			replayed++
+		}
+
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			return replayed, skipped, fmt.Errorf("could not commit message %d/%d: %w", msg.Partition, msg.Offset, err)
+		}
+	}
+	return replayed, skipped, nil
+}
+
+// runReplay is the replay-dlq command: it moves AGGREGATOR_DLQ_TOPIC back to KAFKA_TOPIC
+func runReplay(args []string, logger *log.Logger) error {
+	flags := flag.NewFlagSet("replay-dlq", flag.ContinueOnError)
+	var opts ReplayOptions
+	flags.IntVar(&opts.Limit, "limit", 0, "stop after this many messages, 0 replays all")
+	flags.DurationVar(&opts.Idle, "idle", 10*time.Second, "stop once no message arrived for this long")
+	flags.IntVar(&opts.MaxAttempts, "max-attempts", 0, "skip payloads that failed this often, 0 replays all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if opts.Idle <= 0 {
+		return fmt.Errorf("-idle must be positive")
+	}
+
+	brokers, err := gomonkafka.GetKafkaBrokers()
+	if err != nil {
+		return err
+	}
+	topic, err := gomonkafka.GetKafkaTopic()
+	if err != nil {
+		return err
+	}
+	dlqTopic := os.Getenv("AGGREGATOR_DLQ_TOPIC")
+	if dlqTopic == "" {
+		return fmt.Errorf("AGGREGATOR_DLQ_TOPIC environment variable is not set")
+	}
+	security, err := gomonkafka.GetSecurityConfig()
+	if err != nil {
+		return fmt.Errorf("invalid Kafka security settings: %w", err)
+	}
+	dialer, err := security.Dialer()
+	if err != nil {
+		return fmt.Errorf("could not build Kafka dialer: %w", err)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     strings.Split(brokers, ","),
+		GroupID:     "metrics-dlq-replay",
+		Topic:       dlqTopic,
+		Dialer:      dialer,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	cfg := gomonkafka.DefaultProducerConfig()
+	cfg.Security = security
+	cfg.Key = dlqTopic // unused, every message keeps its original key
+	producer, err := gomonkafka.NewKafkaProducerWithConfig(brokers, topic, cfg)
+	if err != nil {
+		return err
+	}
+	defer producer.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Printf("Replaying %s to %s", dlqTopic, topic)
+	replayed, skipped, err := replayDeadLetters(ctx, reader, producer.Writer, opts, logger)
+	logger.Printf("Replayed %d messages, skipped %d", replayed, skipped)
+	return err
+}
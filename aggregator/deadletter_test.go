@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"gomon/schema"
+	"gomon/testutils"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	topic := &recordingWriter{}
+	dlq := &DeadLetterQueue{topic: "metrics-dlq", writer: topic, logger: quietLogger}
+
+	// A payload that does not decode is dead-lettered as is
+	poison := kafka.Message{Topic: "metrics-v4", Partition: 2, Offset: 41, Key: []byte("node-a"), Value: []byte{0xff, 0xff, 0xff}}
+	if err := processAndSendMetrics(poison, quietLogger, time.Now(), tracer, &stubSink{name: "vm"}, dlq); err == nil {
+		t.Fatal("Expected a decode error")
+	}
+
+	// A payload the sinks failed to write, replayed once already
+	raw, err := schema.Encode(testutils.CreateMetric(), schema.V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := kafka.Message{
+		Topic: "metrics-v4", Offset: 42, Key: []byte("node-a"), Value: raw,
+		Headers: []kafka.Header{{Key: "traceparent", Value: []byte("00-trace")}, {Key: headerDLQAttempts, Value: []byte("1")}},
+	}
+	healthy := &stubSink{name: SinkElasticsearch}
+	vm := &stubSink{name: SinkVictoriaMetrics, err: errors.New("503")}
+	remote := &stubSink{name: SinkRemoteWrite, err: errors.New("503")}
+	sinks := NewFanOut(vm, healthy, remote)
+	if err := processAndSendMetrics(replayed, quietLogger, time.Now(), tracer, sinks, dlq); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replayed again, it only goes to the sinks that failed, one of which recovered
+	remote.err = nil
+	again := topic.messages[1]
+	again.Headers = withoutDeadLetterHeaders(again.Headers, true)
+	if err := processAndSendMetrics(again, quietLogger, time.Now(), tracer, sinks, dlq); err != nil {
+		t.Fatal(err)
+	}
+	if healthy.writes != 1 || vm.writes != 2 || remote.writes != 2 {
+		t.Errorf("Expected the replay to skip the healthy sink, got %d, %d and %d writes", healthy.writes, vm.writes, remote.writes)
+	}
+
+	if len(topic.messages) != 3 {
+		t.Fatalf("Expected 3 dead letters, got %d", len(topic.messages))
+	}
+	dead := topic.messages[0]
+	if string(dead.Value) != string(poison.Value) || string(dead.Key) != "node-a" {
+		t.Errorf("Expected the original payload and key, got %q %q", dead.Value, dead.Key)
+	}
+	for key, value := range map[string]string{
+		headerDLQStage: StageDecode, headerDLQAttempts: "1", headerDLQTopic: "metrics-v4",
+		headerDLQPartition: "2", headerDLQOffset: "41",
+	} {
+		if got := header(dead.Headers, key); got != value {
+			t.Errorf("Header %s: expected %q, got %q", key, value, got)
+		}
+	}
+	if header(dead.Headers, headerDLQError) == "" {
+		t.Error("Expected the decode error in the headers")
+	}
+
+	dead = topic.messages[1]
+	if header(dead.Headers, headerDLQStage) != StageWrite || header(dead.Headers, headerDLQAttempts) != "2" {
+		t.Errorf("Expected a second failed write attempt, got %v", dead.Headers)
+	}
+	if got := header(dead.Headers, headerDLQError); got != "victoriametrics: 503; remote-write: 503" {
+		t.Errorf("Expected the sink errors on one line, got %q", got)
+	}
+	if got := header(dead.Headers, headerDLQSinks); got != "victoriametrics,remote-write" {
+		t.Errorf("Expected the failed sinks, got %q", got)
+	}
+	if header(dead.Headers, "traceparent") != "00-trace" {
+		t.Error("Expected the trace context to be kept")
+	}
+
+	dead = topic.messages[2]
+	if header(dead.Headers, headerDLQSinks) != SinkVictoriaMetrics || header(dead.Headers, headerDLQAttempts) != "3" {
+		t.Errorf("Expected the sink still failing, got %v", dead.Headers)
+	}
+
+	// Dead-lettering is optional
+	var disabled *DeadLetterQueue
+	disabled.Send(poison, StageDecode, errors.New("bad payload"))
+	disabled.Close()
+}
+
+func TestDeadLetterDelivery(t *testing.T) {
+	dlq := &DeadLetterQueue{topic: "metrics-dlq", logger: quietLogger}
+	msg := kafka.Message{Topic: "metrics-v4", Value: []byte("payload")}
+	dead := kafka.Message{Value: msg.Value, Headers: deadLetterHeaders(msg, StageQueue, errors.New("closed"), time.Now())}
+
+	sent := testutil.ToFloat64(deadLetters.WithLabelValues(StageQueue))
+	lost := testutil.ToFloat64(deadLetterErrors)
+
+	// A lost dead letter is counted as lost only
+	dlq.delivered([]kafka.Message{dead}, errors.New("broker down"))
+	if testutil.ToFloat64(deadLetters.WithLabelValues(StageQueue)) != sent || testutil.ToFloat64(deadLetterErrors) != lost+1 {
+		t.Error("Expected a failed delivery counted as an error only")
+	}
+
+	dlq.delivered([]kafka.Message{dead}, nil)
+	if testutil.ToFloat64(deadLetters.WithLabelValues(StageQueue)) != sent+1 || testutil.ToFloat64(deadLetterErrors) != lost+1 {
+		t.Error("Expected a delivered dead letter counted by its stage")
+	}
+}
+
+// dlqTopic hands out its messages, then waits like an idle topic
+type dlqTopic struct {
+	messages  []kafka.Message
+	committed []int64
+}
+
+func (r *dlqTopic) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *dlqTopic) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func TestReplayDeadLetters(t *testing.T) {
+	failed := func(offset int64, attempts string) kafka.Message {
+		return kafka.Message{Offset: offset, Key: []byte("node-a"), Value: []byte("payload"), Headers: []kafka.Header{
+			{Key: "traceparent", Value: []byte("00-trace")},
+			{Key: headerDLQError, Value: []byte("503")},
+			{Key: headerDLQStage, Value: []byte(StageWrite)},
+			{Key: headerDLQAttempts, Value: []byte(attempts)},
+			{Key: headerDLQSinks, Value: []byte(SinkRemoteWrite)},
+		}}
+	}
+	reader := &dlqTopic{messages: []kafka.Message{failed(0, "1"), failed(1, "3"), failed(2, "2")}}
+	writer := &recordingWriter{}
+
+	opts := ReplayOptions{Idle: 10 * time.Millisecond, MaxAttempts: 3}
+	replayed, skipped, err := replayDeadLetters(context.Background(), reader, writer, opts, quietLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != 2 || skipped != 1 || len(reader.committed) != 3 {
+		t.Errorf("Expected 2 replayed, 1 skipped and 3 committed, got %d, %d and %v", replayed, skipped, reader.committed)
+	}
+
+	msg := writer.messages[0]
+	if string(msg.Value) != "payload" || string(msg.Key) != "node-a" {
+		t.Errorf("Expected the original payload, got %q", msg.Value)
+	}
+	if header(msg.Headers, headerDLQError) != "" || header(msg.Headers, headerDLQStage) != "" {
+		t.Errorf("Expected the failure headers to be dropped, got %v", msg.Headers)
+	}
+	if header(msg.Headers, headerDLQAttempts) != "1" || header(msg.Headers, headerDLQSinks) != SinkRemoteWrite ||
+		header(msg.Headers, "traceparent") != "00-trace" {
+		t.Errorf("Expected attempts, failed sinks and trace context to be kept, got %v", msg.Headers)
+	}
+
+	// Limit stops early
+	reader = &dlqTopic{messages: []kafka.Message{failed(0, "1"), failed(1, "1")}}
+	opts.Limit = 1
+	if replayed, _, _ := replayDeadLetters(context.Background(), reader, &recordingWriter{}, opts, quietLogger); replayed != 1 || len(reader.messages) != 1 {
+		t.Errorf("Expected one message replayed, got %d", replayed)
+	}
+}
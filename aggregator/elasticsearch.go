@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gomon/pb"
+	"gomon/schema"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ElasticsearchConfig points the aggregator at an Elasticsearch or OpenSearch
+// data stream that keeps every payload as a searchable document
+type ElasticsearchConfig struct {
+	URL        string
+	DataStream string
+	// ILMPolicy manages the backing indices, see elasticsearch/ilm/ilm.json.
+	// Empty for OpenSearch, which has ISM instead.
+	ILMPolicy string
+	Username  string
+	Password  string
+	APIKey    string
+	Batch     BatchConfig
+}
+
+// GetElasticsearchConfig reads ELASTICSEARCH_URL, ELASTICSEARCH_DATA_STREAM,
+// ELASTICSEARCH_ILM_POLICY, the credentials and the ELASTICSEARCH_* batching overrides
+func GetElasticsearchConfig() (ElasticsearchConfig, error) {
+	cfg := ElasticsearchConfig{
+		URL:        strings.TrimSuffix(os.Getenv("ELASTICSEARCH_URL"), "/"),
+		DataStream: "gomon-metrics",
+		ILMPolicy:  "aggregator-rollover-policy",
+		Username:   os.Getenv("ELASTICSEARCH_USERNAME"),
+		Password:   os.Getenv("ELASTICSEARCH_PASSWORD"),
+		APIKey:     os.Getenv("ELASTICSEARCH_API_KEY"),
+	}
+
+	if v := os.Getenv("ELASTICSEARCH_DATA_STREAM"); v != "" {
+		cfg.DataStream = v
+	}
+
+	// Set but empty disables ILM
+	if v, ok := os.LookupEnv("ELASTICSEARCH_ILM_POLICY"); ok {
+		cfg.ILMPolicy = v
+	}
+
+	// Documents are whole payloads, far larger than series
+	defaults := DefaultBatchConfig()
+	defaults.MaxSeries = 500
+	batch, err := getBatchConfig("ELASTICSEARCH", defaults)
+	if err != nil {
+		return ElasticsearchConfig{}, err
+	}
+	cfg.Batch = batch
+
+	return cfg, cfg.Validate()
+}
+
+// Validate reports the first problem with the Elasticsearch settings
+func (c ElasticsearchConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("ELASTICSEARCH_URL environment variable is not set")
+	}
+	// Data stream names are lowercase and must not start with - _ + or .
+	if c.DataStream == "" || c.DataStream != strings.ToLower(c.DataStream) || strings.ContainsAny(c.DataStream[:1], "-_+.") ||
+		strings.ContainsAny(c.DataStream, ` "*\<|,>/?#:`) {
+		return fmt.Errorf("invalid data stream name %q", c.DataStream)
+	}
+	if c.APIKey != "" && c.Username != "" {
+		return fmt.Errorf("set either ELASTICSEARCH_API_KEY or ELASTICSEARCH_USERNAME, not both")
+	}
+	return nil
+}
+
+// elasticsearch bulk-indexes one document per payload into a data stream
+type elasticsearch struct {
+	cfg ElasticsearchConfig
+}
+
+func (e elasticsearch) name() string { return "elasticsearch" }
+
+// esDocument is a payload as indexed: the fields worth searching on top, the
+// message itself stored but not indexed under metric
+type esDocument struct {
+	Timestamp     string            `json:"@timestamp"`
+	Host          esHost            `json:"host"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	TraceID       string            `json:"trace_id,omitempty"`
+	SchemaVersion uint32            `json:"schema_version"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Metric        json.RawMessage   `json:"metric"`
+}
+
+type esHost struct {
+	Name string `json:"name"`
+	ID   string `json:"id,omitempty"`
+}
+
+// encode appends a create action and the payload document. The correlation id is
+// the document id, so a retried batch does not index a payload twice.
+func (e elasticsearch) encode(buf *bytes.Buffer, payload *Payload) (int, error) {
+	batch := payload.Batch
+	if batch == nil {
+		return 0, fmt.Errorf("payload has no decoded batch")
+	}
+
+	// v1 payloads are indexed as the pb.Metric the agent sent
+	var message proto.Message = batch
+	if payload.Version == schema.V1 && len(payload.Raw) > 0 {
+		metric := &pb.Metric{}
+		if err := proto.Unmarshal(payload.Raw, metric); err != nil {
+			return 0, fmt.Errorf("could not unmarshal v1 payload: %w", err)
+		}
+		message = metric
+	}
+	raw, err := protojson.Marshal(message)
+	if err != nil {
+		return 0, fmt.Errorf("could not marshal payload: %w", err)
+	}
+
+	collected := batch.TraceStartTimeMs
+	if collected == 0 {
+		collected = batch.AggregatorReceivedTimeMs
+	}
+	doc := esDocument{
+		Timestamp:     time.UnixMilli(collected).UTC().Format(time.RFC3339Nano),
+		Host:          esHost{Name: batch.Hostname, ID: batch.GetHost().GetMachineId()},
+		CorrelationID: batch.CorrelationId,
+		TraceID:       payload.TraceID,
+		SchemaVersion: payload.Version,
+		Labels:        batch.Labels,
+		Metric:        raw,
+	}
+
+	action := map[string]map[string]string{"create": {}}
+	if batch.CorrelationId != "" {
+		action["create"]["_id"] = batch.CorrelationId
+	}
+	for _, line := range []interface{}{action, doc} {
+		data, err := json.Marshal(line)
+		if err != nil {
+			return 0, fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return 1, nil
+}
+
+func (e elasticsearch) send(client *http.Client, batch []byte) error {
+	req, err := e.request(http.MethodPost, "/"+e.cfg.DataStream+"/_bulk", batch)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+
+	// A 200 answer may still hold failed documents
+	var result esBulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("could not decode bulk response: %w", err)
+	}
+	return result.err()
+}
+
+// esBulkResponse is the part of a _bulk answer that tells failed documents apart
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// err turns the failed items into a statusError. Documents that already exist
+// were written by an earlier attempt; the batch is retried when any item may
+// succeed later (429 or 5xx), which is safe as ids make creates idempotent.
+func (r esBulkResponse) err() error {
+	if !r.Errors {
+		return nil
+	}
+	var failed, code int
+	var first string
+	for _, item := range r.Items {
+		for _, result := range item {
+			if result.Error == nil || result.Status == http.StatusConflict {
+				continue
+			}
+			failed++
+			if first == "" {
+				first = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+			}
+			if result.Status == http.StatusTooManyRequests || result.Status >= 500 || code == 0 {
+				code = result.Status
+			}
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return &statusError{code: code, msg: fmt.Sprintf("%d of %d documents failed, first: %s", failed, len(r.Items), first)}
+}
+
+func (e elasticsearch) request(method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, e.cfg.URL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not create HTTP request: %w", err)
+	}
+	switch {
+	case e.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.cfg.APIKey)
+	case e.cfg.Username != "":
+		req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	}
+	return req, nil
+}
+
+// setup checks the ILM policy exists and creates or updates the index template
+// of the data stream. The first bulk create then creates the data stream itself.
+func (e elasticsearch) setup(client *http.Client) error {
+	if e.cfg.ILMPolicy != "" {
+		req, err := e.request(http.MethodGet, "/_ilm/policy/"+e.cfg.ILMPolicy, nil)
+		if err != nil {
+			return err
+		}
+		if err := doWrite(client, req); err != nil {
+			return fmt.Errorf("ILM policy %s is not available, apply elasticsearch/ilm/ilm.json or set ELASTICSEARCH_ILM_POLICY: %w", e.cfg.ILMPolicy, err)
+		}
+	}
+
+	body, err := json.Marshal(e.indexTemplate())
+	if err != nil {
+		return fmt.Errorf("could not marshal index template: %w", err)
+	}
+	req, err := e.request(http.MethodPut, "/_index_template/"+e.cfg.DataStream, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := doWrite(client, req); err != nil {
+		return fmt.Errorf("could not create index template %s: %w", e.cfg.DataStream, err)
+	}
+	return nil
+}
+
+// indexTemplate maps the searchable fields. The payload under metric is kept in
+// _source only: its labels and sample names would otherwise grow the mapping without bound.
+func (e elasticsearch) indexTemplate() map[string]interface{} {
+	settings := map[string]interface{}{
+		"index.number_of_shards": 1,
+	}
+	if e.cfg.ILMPolicy != "" {
+		settings["index.lifecycle.name"] = e.cfg.ILMPolicy
+	}
+
+	keyword := map[string]string{"type": "keyword"}
+	return map[string]interface{}{
+		"index_patterns": []string{e.cfg.DataStream + "*"},
+		"data_stream":    map[string]interface{}{},
+		// Above the built-in templates that match logs-*-* and metrics-*-*
+		"priority": 200,
+		"template": map[string]interface{}{
+			"settings": settings,
+			"mappings": map[string]interface{}{
+				"dynamic": false,
+				"dynamic_templates": []map[string]interface{}{
+					{"labels": map[string]interface{}{"path_match": "labels.*", "mapping": keyword}},
+				},
+				"properties": map[string]interface{}{
+					"@timestamp": map[string]string{"type": "date"},
+					"host": map[string]interface{}{
+						"properties": map[string]interface{}{"name": keyword, "id": keyword},
+					},
+					"correlation_id": keyword,
+					"trace_id":       keyword,
+					"schema_version": map[string]string{"type": "integer"},
+					"labels":         map[string]interface{}{"type": "object", "dynamic": true},
+					"metric":         map[string]interface{}{"type": "object", "enabled": false},
+				},
+			},
+		},
+	}
+}
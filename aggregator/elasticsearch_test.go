@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"gomon/testutils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestElasticsearchSink(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		template  map[string]interface{}
+		bulks     []string
+		hasPolicy = true
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if user, pass, _ := r.BasicAuth(); user != "gomon" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /_ilm/policy/aggregator-rollover-policy":
+			if !hasPolicy {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "PUT /_index_template/gomon-metrics":
+			json.NewDecoder(r.Body).Decode(&template)
+		case "POST /gomon-metrics/_bulk":
+			body, _ := io.ReadAll(r.Body)
+			bulks = append(bulks, string(body))
+			// The first attempt is rejected, the retry finds the document already created
+			if len(bulks) == 1 {
+				w.Write([]byte(`{"errors":true,"items":[{"create":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"queue is full"}}}]}`))
+			} else {
+				w.Write([]byte(`{"errors":true,"items":[{"create":{"status":409,"error":{"type":"version_conflict_engine_exception","reason":"document already exists"}}}]}`))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AGGREGATOR_SINKS", SinkElasticsearch)
+	t.Setenv("ELASTICSEARCH_URL", server.URL+"/")
+	t.Setenv("ELASTICSEARCH_USERNAME", "gomon")
+	t.Setenv("ELASTICSEARCH_PASSWORD", "secret")
+	t.Setenv("ELASTICSEARCH_BATCH_SIZE", "1")
+	t.Setenv("ELASTICSEARCH_RETRY_BACKOFF", "1ms")
+	sinks, err := NewSinks(quietLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := template["template"].(map[string]interface{})["settings"].(map[string]interface{})
+	if settings["index.lifecycle.name"] != "aggregator-rollover-policy" || template["data_stream"] == nil {
+		t.Errorf("Expected a data stream template with the ILM policy, got %v", template)
+	}
+
+	metric := testutils.CreateMetric()
+	metric.Hostname = "node-a"
+	metric.CorrelationId = "corr-1"
+	payload := v1Payload(t, metric)
+	payload.TraceID = "trace-1"
+	flushErr := writeAndWait(t, sinks, payload)
+	sinks.Close()
+
+	if flushErr != nil {
+		t.Errorf("Expected the conflict on retry to count as written, got %v", flushErr)
+	}
+	if len(bulks) != 2 || bulks[0] != bulks[1] {
+		t.Fatalf("Expected the bulk request to be retried once, got %d", len(bulks))
+	}
+	lines := strings.Split(strings.TrimSpace(bulks[0]), "\n")
+	if len(lines) != 2 || lines[0] != `{"create":{"_id":"corr-1"}}` {
+		t.Fatalf("Expected a create action with the correlation id, got %v", lines)
+	}
+	var doc struct {
+		Host          esHost                 `json:"host"`
+		CorrelationID string                 `json:"correlation_id"`
+		TraceID       string                 `json:"trace_id"`
+		Metric        map[string]interface{} `json:"metric"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Host.Name != "node-a" || doc.CorrelationID != "corr-1" || doc.TraceID != "trace-1" {
+		t.Errorf("Unexpected document fields: %+v", doc)
+	}
+	// v1 payloads keep their own fields
+	if doc.Metric["cpuUsagePercent"] != float64(44) {
+		t.Errorf("Expected the v1 metric in the document, got %v", doc.Metric)
+	}
+
+	mu.Lock()
+	hasPolicy = false
+	mu.Unlock()
+	if _, err := NewSinks(quietLogger); err == nil {
+		t.Error("Expected error when the ILM policy is missing")
+	}
+	t.Setenv("ELASTICSEARCH_ILM_POLICY", "")
+	sinks, err = NewSinks(quietLogger)
+	if err != nil {
+		t.Fatalf("Expected ILM to be optional: %v", err)
+	}
+	sinks.Close()
+}
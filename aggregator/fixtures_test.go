@@ -0,0 +1,166 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"gomon/pb"
+	"gomon/schema"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Fixtures shared by the aggregator tests
+
+var quietLogger = log.New(io.Discard, "", 0)
+
+// v1Payload encodes metric as v1 and decodes it the way the aggregator does
+func v1Payload(t *testing.T, metric *pb.Metric) *Payload {
+	t.Helper()
+	raw, err := schema.Encode(metric, schema.V1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, version, err := schema.Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Payload{Raw: raw, Version: version, Batch: batch}
+}
+
+// metricSeries builds the series of a v1 metric
+func metricSeries(t *testing.T, metric *pb.Metric, instance string) []map[string]interface{} {
+	t.Helper()
+	return buildSampleData(v1Payload(t, metric).Batch, instance)
+}
+
+func testSeries(name string, labels map[string]string) map[string]interface{} {
+	return createSeries(name, 1, 1700000000000, "node-a", labels)
+}
+
+func seriesPayload(series ...map[string]interface{}) *Payload {
+	return &Payload{Series: series}
+}
+
+// writeAndWait writes payload to sink and returns the error it was flushed with
+func writeAndWait(t *testing.T, sink Sink, payload *Payload) error {
+	t.Helper()
+	flushed := make(chan error, 1)
+	if err := sink.Write(payload, func(err error) { flushed <- err }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-flushed:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the payload to be flushed")
+		return nil
+	}
+}
+
+// jsonImport writes JSON lines to the import endpoint at url
+func jsonImport(url string) vmImport {
+	return vmImport{cfg: VMImportConfig{URL: url, Format: FormatJSON}}
+}
+
+// vmImportServer records the decoded bodies of the import requests it receives
+type vmImportServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   []string
+	paths    []string
+	encoding []string
+}
+
+func newVMImportServer(t *testing.T) *vmImportServer {
+	s := &vmImportServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("Invalid gzip body: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		data, _ := io.ReadAll(body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(data))
+		s.paths = append(s.paths, r.URL.Path)
+		s.encoding = append(s.encoding, r.Header.Get("Content-Encoding"))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *vmImportServer) requests() ([]string, []string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...), append([]string(nil), s.paths...), append([]string(nil), s.encoding...)
+}
+
+// statusServer answers each request with the status returned for its attempt number
+type statusServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	attempts int
+}
+
+func newStatusServer(t *testing.T, status func(attempt int) int) *statusServer {
+	s := &statusServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.attempts++
+		attempt := s.attempts
+		s.mu.Unlock()
+		w.WriteHeader(status(attempt))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *statusServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// recordingWriter keeps the messages written to a topic
+type recordingWriter struct {
+	mu       sync.Mutex
+	messages []kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// stubSink reports every payload with err and counts the writes
+type stubSink struct {
+	name   string
+	err    error
+	writes int
+}
+
+func (s *stubSink) Name() string { return s.name }
+func (s *stubSink) Write(payload *Payload, done func(error)) error {
+	s.writes++
+	done(s.err)
+	return nil
+}
+func (s *stubSink) Health() error { return s.err }
+func (s *stubSink) Close()        {}
@@ -2,19 +2,151 @@ package main
 
 import (
 	"strconv"
+	"time"
+
+	"gomon/pb"
+	"gomon/schema"
 )
 
-func CreateMetricData(metricName string, value float64, timestampStr string, correlationID string, hostname string) map[string]interface{} {
+func CreateMetricData(metricName string, value float64, timestampStr string, instance string) map[string]interface{} {
+	return CreateMetricDataWithLabels(metricName, value, timestampStr, instance, nil)
+}
+
+// CreateMetricDataWithLabels is CreateMetricData with extra series labels (interface, mountpoint, ...)
+func CreateMetricDataWithLabels(metricName string, value float64, timestampStr string, instance string, labels map[string]string) map[string]interface{} {
 	timestamp, _ := strconv.ParseInt(timestampStr, 10, 64)
+	return createSeries(metricName, value, timestamp*1000, instance, labels)
+}
+
+// createSeries builds one VictoriaMetrics import line, timestamp in milliseconds.
+// The per-cycle correlation id is not a label, it would start a new series
+// every cycle; it is on the trace, the logs and the latency exemplars instead.
+func createSeries(metricName string, value float64, timestampMs int64, instance string, labels map[string]string) map[string]interface{} {
+	metricLabels := map[string]string{
+		"__name__": metricName,
+		"job":      "metrics-aggregator",
+		"instance": instance,
+	}
+	for k, v := range labels {
+		metricLabels[k] = v
+	}
 
 	return map[string]interface{}{
-		"metric": map[string]string{
-			"__name__":       metricName,
-			"job":            "metrics-aggregator",
-			"instance":       hostname + "-agg",
-			"correlation_id": correlationID,
-		},
+		"metric":     metricLabels,
 		"values":     []float64{value},
-		"timestamps": []int64{timestamp * 1000},
+		"timestamps": []int64{timestampMs},
+	}
+}
+
+// instanceLabel names the host a payload describes: the agent's hostname,
+// else its Kubernetes node for agents that could not resolve one
+func instanceLabel(hostname string, host *pb.HostIdentity) string {
+	if hostname != "" {
+		return hostname
+	}
+	if node := host.GetNodeName(); node != "" {
+		return node
+	}
+	return "unknown"
+}
+
+// buildSampleData maps a v2 batch to VictoriaMetrics import series. Histograms
+// become the usual _bucket, _sum and _count series.
+func buildSampleData(batch *pb.MetricBatch, instance string) []map[string]interface{} {
+	var metricsData []map[string]interface{}
+
+	// Host labels go on every series, a sample label with the same name wins
+	hostLabels := validHostLabels(batch.Labels)
+	add := func(name string, value float64, timestampMs int64, labels map[string]string) {
+		if len(hostLabels) > 0 {
+			merged := make(map[string]string, len(hostLabels)+len(labels))
+			for k, v := range hostLabels {
+				merged[k] = v
+			}
+			for k, v := range labels {
+				merged[k] = v
+			}
+			labels = merged
+		}
+		metricsData = append(metricsData,
+			createSeries(name, value, timestampMs, instance, labels))
+	}
+
+	// Info series joining the instance to the rest of the host identity
+	if host := batch.GetHost(); host != nil {
+		labels := map[string]string{}
+		for name, value := range map[string]string{
+			"machine_id":        host.MachineId,
+			"node":              host.NodeName,
+			"cloud_provider":    host.CloudProvider,
+			"cloud_instance_id": host.CloudInstanceId,
+		} {
+			if value != "" {
+				labels[name] = value
+			}
+		}
+		add("gomon_host_info", 1, batchTimestamp(batch), labels)
+	}
+
+	for _, sample := range batch.Samples {
+		if sample == nil || sample.Name == "" {
+			continue
+		}
+		if sample.Type != pb.Sample_HISTOGRAM {
+			add(sample.Name, sample.Value, sample.TimestampMs, sample.Labels)
+			continue
+		}
+
+		h := sample.Histogram
+		if h == nil {
+			continue
+		}
+		for i, bound := range h.UpperBounds {
+			if i >= len(h.BucketCounts) {
+				break
+			}
+			add(sample.Name+"_bucket", float64(h.BucketCounts[i]), sample.TimestampMs,
+				withLabel(sample.Labels, "le", strconv.FormatFloat(bound, 'g', -1, 64)))
+		}
+		add(sample.Name+"_bucket", float64(h.Count), sample.TimestampMs, withLabel(sample.Labels, "le", "+Inf"))
+		add(sample.Name+"_sum", h.Sum, sample.TimestampMs, sample.Labels)
+		add(sample.Name+"_count", float64(h.Count), sample.TimestampMs, sample.Labels)
+	}
+
+	return metricsData
+}
+
+// validHostLabels drops labels an agent should not have sent: invalid names
+// and the ones the aggregator sets itself
+func validHostLabels(labels map[string]string) map[string]string {
+	valid := make(map[string]string, len(labels))
+	for name, value := range labels {
+		if value != "" && schema.ValidateHostLabel(name) == nil {
+			valid[name] = value
+		}
+	}
+	return valid
+}
+
+// batchTimestamp is the collection time of a batch, for series not tied to a sample
+func batchTimestamp(batch *pb.MetricBatch) int64 {
+	for _, sample := range batch.Samples {
+		if sample.GetTimestampMs() > 0 {
+			return sample.TimestampMs
+		}
+	}
+	if batch.TraceStartTimeMs > 0 {
+		return batch.TraceStartTimeMs
+	}
+	return time.Now().UnixMilli()
+}
+
+// withLabel copies labels and adds name=value
+func withLabel(labels map[string]string, name, value string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
 	}
+	out[name] = value
+	return out
 }
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"gomon/pb"
+	"gomon/schema"
+	"gomon/testutils"
+	"testing"
+)
+
+// Network rates and raw counters are labelled per interface
+func TestNetworkSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.NetStats = []*pb.NetworkUsage{
+		{InterfaceName: "eth0", BytesSent: 4 << 20, RateIntervalSeconds: 20, BytesSentPerSec: 1024},
+		{InterfaceName: "veth1", BytesSent: 1 << 20},
+	}
+
+	series := make(map[string][]map[string]interface{})
+	for _, data := range metricSeries(t, metric, "host-1") {
+		labels := data["metric"].(map[string]string)
+		series[labels["__name__"]] = append(series[labels["__name__"]], data)
+	}
+
+	if got := len(series["int_bytes_sent_mb"]); got != 2 {
+		t.Fatalf("Expected raw counters for 2 interfaces, got %d", got)
+	}
+	if iface := series["int_bytes_sent_mb"][1]["metric"].(map[string]string)["interface"]; iface != "veth1" {
+		t.Errorf("Expected interface label veth1, got %q", iface)
+	}
+
+	rates := series["net_bytes_sent_per_sec"]
+	if len(rates) != 1 {
+		t.Fatalf("Expected rates only for eth0, got %d series", len(rates))
+	}
+	if value := rates[0]["values"].([]float64)[0]; value != 1024 {
+		t.Errorf("Expected 1024 B/s, got %v", value)
+	}
+}
+
+// Memory stats are exported as distinct byte series
+func TestMemorySeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.MemoryStats = &pb.MemoryStats{
+		TotalBytes:     16 << 30,
+		AvailableBytes: 9 << 30,
+		SwapUsedBytes:  1 << 30,
+	}
+
+	values := make(map[string]float64)
+	for _, data := range metricSeries(t, metric, "host-1") {
+		values[data["metric"].(map[string]string)["__name__"]] = data["values"].([]float64)[0]
+	}
+
+	if values["mem_total_bytes"] != 16<<30 {
+		t.Errorf("Expected mem_total_bytes=%d, got %v", 16<<30, values["mem_total_bytes"])
+	}
+	if values["mem_available_bytes"] != 9<<30 {
+		t.Errorf("Expected mem_available_bytes=%d, got %v", 9<<30, values["mem_available_bytes"])
+	}
+	if values["swap_used_bytes"] != 1<<30 {
+		t.Errorf("Expected swap_used_bytes=%d, got %v", 1<<30, values["swap_used_bytes"])
+	}
+	if _, ok := values["dsk_used_gb"]; ok {
+		t.Error("Memory must not be exported as dsk_used_gb")
+	}
+}
+
+// Per-core CPU and mode breakdown are labelled series
+func TestCPUSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.CpuStats = &pb.CpuStats{
+		Total: &pb.CpuTimesPercent{Cpu: "cpu-total", UsagePercent: 40, UserPercent: 30, IowaitPercent: 10},
+		Cores: []*pb.CpuTimesPercent{
+			{Cpu: "cpu0", UsagePercent: 50},
+			{Cpu: "cpu1", UsagePercent: 30},
+		},
+		Load1: 1.5,
+	}
+
+	var coreSeries, modeSeries int
+	var load1 float64
+	for _, data := range metricSeries(t, metric, "host-1") {
+		labels := data["metric"].(map[string]string)
+		switch labels["__name__"] {
+		case "cpu_core_usage_percent":
+			coreSeries++
+			if labels["cpu"] == "" {
+				t.Error("cpu_core_usage_percent missing cpu label")
+			}
+		case "cpu_mode_percent":
+			modeSeries++
+			if labels["mode"] == "iowait" && data["values"].([]float64)[0] != 10 {
+				t.Errorf("Expected iowait 10, got %v", data["values"])
+			}
+		case "load_average_1m":
+			load1 = data["values"].([]float64)[0]
+		}
+	}
+
+	if coreSeries != 2 {
+		t.Errorf("Expected 2 per-core series, got %d", coreSeries)
+	}
+	if modeSeries != 8 {
+		t.Errorf("Expected 8 mode series, got %d", modeSeries)
+	}
+	if load1 != 1.5 {
+		t.Errorf("Expected load1 1.5, got %v", load1)
+	}
+}
+
+// Disk space, inode and I/O series carry mountpoint/device labels
+func TestDiskSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.DiskStats = []*pb.DiskUsage{
+		{Mountpoint: "/", UsedPercent: 40, InodesTotal: 1000, InodesUsedPercent: 12.5},
+	}
+	metric.DiskIoStats = []*pb.DiskIOStats{
+		{Device: "sda", RateIntervalSeconds: 20, UtilizationPercent: 93, AvgAwaitMs: 14},
+		{Device: "sdb"},
+	}
+
+	found := make(map[string]map[string]string)
+	var utilSeries int
+	for _, data := range metricSeries(t, metric, "host-1") {
+		labels := data["metric"].(map[string]string)
+		found[labels["__name__"]] = labels
+		if labels["__name__"] == "disk_utilization_percent" {
+			utilSeries++
+		}
+	}
+
+	if found["disk_used_percent"]["mountpoint"] != "/" {
+		t.Errorf("disk_used_percent missing mountpoint label: %v", found["disk_used_percent"])
+	}
+	if found["disk_inodes_used_percent"] == nil {
+		t.Error("Expected disk_inodes_used_percent series")
+	}
+	if found["disk_await_ms"]["device"] != "sda" {
+		t.Errorf("disk_await_ms missing device label: %v", found["disk_await_ms"])
+	}
+	if utilSeries != 1 {
+		t.Errorf("Expected utilisation only for devices with rates, got %d", utilSeries)
+	}
+}
+
+func TestProcessSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.ProcessStats = []*pb.ProcessUsage{
+		{Pid: 42, Name: "postgres", User: "postgres", CmdlineHash: "0123abcd", CpuPercent: 37.5, RssBytes: 1 << 30, TopBy: []string{"cpu", "rss"}},
+		{Pid: 43, Name: "postgres", User: "postgres", CmdlineHash: "0123abcd", CpuPercent: 2.5, RssBytes: 1 << 20, TopBy: []string{"rss"}},
+	}
+
+	found := make(map[string]map[string]interface{})
+	for _, data := range metricSeries(t, metric, "host-1") {
+		labels := data["metric"].(map[string]string)
+		found[labels["__name__"]] = data
+	}
+
+	cpu := found["process_cpu_percent"]
+	if cpu == nil {
+		t.Fatal("Expected process_cpu_percent series")
+	}
+	labels := cpu["metric"].(map[string]string)
+	if _, ok := labels["pid"]; ok || labels["process"] != "postgres" || labels["user"] != "postgres" || labels["cmdline_hash"] != "0123abcd" {
+		t.Errorf("Unexpected process labels: %v", labels)
+	}
+	// Processes sharing their labels are summed
+	if v := cpu["values"].([]float64)[0]; v != 40 {
+		t.Errorf("Expected CPU 40, got %v", v)
+	}
+	if found["process_rss_bytes"]["values"].([]float64)[0] != float64(1<<30+1<<20) {
+		t.Errorf("Unexpected RSS series: %v", found["process_rss_bytes"])
+	}
+	if found["process_count"]["values"].([]float64)[0] != 2 {
+		t.Errorf("Unexpected process count: %v", found["process_count"])
+	}
+}
+
+func TestContainerSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.ContainerStats = []*pb.ContainerUsage{
+		{ContainerId: "abc123", PodUid: "pod-1", MemoryUsageBytes: 2048, MemoryLimitBytes: 4096, RateIntervalSeconds: 20, CpuUsagePercent: 75},
+		{ContainerId: "def456"},
+	}
+
+	found := make(map[string][]map[string]string)
+	for _, data := range metricSeries(t, metric, "host-1") {
+		labels := data["metric"].(map[string]string)
+		found[labels["__name__"]] = append(found[labels["__name__"]], labels)
+	}
+
+	if usage := found["container_memory_usage_bytes"]; len(usage) != 2 || usage[0]["pod_uid"] != "pod-1" {
+		t.Errorf("Unexpected container_memory_usage_bytes series: %v", usage)
+	}
+	if _, ok := found["container_memory_usage_bytes"][1]["pod_uid"]; ok {
+		t.Error("Containers outside pods must not carry a pod_uid label")
+	}
+	if limits := found["container_memory_limit_bytes"]; len(limits) != 1 {
+		t.Errorf("Expected memory limit only for limited containers, got %v", limits)
+	}
+	if cpu := found["container_cpu_usage_percent"]; len(cpu) != 1 || cpu[0]["container_id"] != "abc123" {
+		t.Errorf("Expected CPU rate only for containers with rates, got %v", cpu)
+	}
+}
+
+func TestPressureSeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.PressureStats = []*pb.PressureStats{
+		{Resource: "io", Kind: "full", Avg10: 12.5, TotalSeconds: 30},
+	}
+
+	var found map[string]interface{}
+	for _, data := range metricSeries(t, metric, "host-1") {
+		if data["metric"].(map[string]string)["__name__"] == "pressure_avg10_percent" {
+			found = data
+		}
+	}
+	if found == nil {
+		t.Fatal("Expected pressure_avg10_percent series")
+	}
+	labels := found["metric"].(map[string]string)
+	if labels["resource"] != "io" || labels["kind"] != "full" {
+		t.Errorf("Unexpected pressure labels: %v", labels)
+	}
+	if v := found["values"].([]float64)[0]; v != 12.5 {
+		t.Errorf("Expected avg10 12.5, got %v", v)
+	}
+}
+
+// Series carry the agent's hostname as instance, the rest of the identity goes to an info series
+func TestHostIdentitySeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.Hostname = "node-a"
+	metric.Host = &pb.HostIdentity{MachineId: "abc123", NodeName: "worker-1", CloudProvider: "aws", CloudInstanceId: "i-0123"}
+
+	var info map[string]string
+	for _, data := range metricSeries(t, metric, instanceLabel(metric.Hostname, metric.Host)) {
+		labels := data["metric"].(map[string]string)
+		if labels["instance"] != "node-a" {
+			t.Fatalf("Expected instance node-a on %s, got %q", labels["__name__"], labels["instance"])
+		}
+		if labels["__name__"] == "gomon_host_info" {
+			info = labels
+		}
+	}
+	if info == nil {
+		t.Fatal("Expected gomon_host_info series")
+	}
+	if info["machine_id"] != "abc123" || info["node"] != "worker-1" || info["cloud_instance_id"] != "i-0123" {
+		t.Errorf("Unexpected host info labels: %v", info)
+	}
+
+	metric.Hostname = ""
+	if got := instanceLabel(metric.Hostname, metric.Host); got != "worker-1" {
+		t.Errorf("Expected node name fallback, got %q", got)
+	}
+}
+
+func TestSampleDataHistogram(t *testing.T) {
+	batch := &pb.MetricBatch{
+		SchemaVersion: schema.V2,
+		Hostname:      "node-a",
+		Samples: []*pb.Sample{
+			{Name: "app_requests_total", Type: pb.Sample_COUNTER, Value: 42, TimestampMs: 1700000000123,
+				Labels: map[string]string{"route": "/api"}},
+			{Name: "app_request_seconds", Type: pb.Sample_HISTOGRAM, TimestampMs: 1700000000123,
+				Histogram: &pb.Histogram{UpperBounds: []float64{0.1, 1}, BucketCounts: []uint64{3, 7}, Count: 9, Sum: 4.2}},
+		},
+	}
+
+	series := map[string]float64{}
+	for _, data := range buildSampleData(batch, "node-a") {
+		labels := data["metric"].(map[string]string)
+		if ts := data["timestamps"].([]int64)[0]; ts != 1700000000123 {
+			t.Errorf("Expected millisecond timestamp to pass through, got %d", ts)
+		}
+		key := labels["__name__"]
+		if le, ok := labels["le"]; ok {
+			key += "{le=" + le + "}"
+		}
+		series[key] = data["values"].([]float64)[0]
+	}
+
+	want := map[string]float64{
+		"app_requests_total":                  42,
+		"app_request_seconds_bucket{le=0.1}":  3,
+		"app_request_seconds_bucket{le=1}":    7,
+		"app_request_seconds_bucket{le=+Inf}": 9,
+		"app_request_seconds_sum":             4.2,
+		"app_request_seconds_count":           9,
+	}
+	if len(series) != len(want) {
+		t.Errorf("Expected %d series, got %v", len(want), series)
+	}
+	for name, v := range want {
+		if got, ok := series[name]; !ok || got != v {
+			t.Errorf("Series %s: expected %v, got %v (present %v)", name, v, got, ok)
+		}
+	}
+}
+
+func TestHostLabelsOnEverySeries(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.Labels = map[string]string{"env": "prod", "mountpoint": "host", "instance": "spoofed", "bad-name": "x"}
+
+	for _, data := range metricSeries(t, metric, "node-a") {
+		labels := data["metric"].(map[string]string)
+		if labels["env"] != "prod" {
+			t.Errorf("Expected env label on %s, got %v", labels["__name__"], labels)
+		}
+		if labels["instance"] != "node-a" {
+			t.Errorf("Host label replaced instance on %s: %v", labels["__name__"], labels)
+		}
+		if _, ok := labels["bad-name"]; ok {
+			t.Errorf("Invalid label name passed through: %v", labels)
+		}
+		if labels["__name__"] == "disk_used_percent" && labels["mountpoint"] != "/dev/da" {
+			t.Errorf("Expected the sample's mountpoint to win, got %q", labels["mountpoint"])
+		}
+	}
+}
+
+// A correlation id label would start a new series every cycle
+func TestSeriesWithoutCorrelationID(t *testing.T) {
+	metric := testutils.CreateMetric()
+	for _, data := range metricSeries(t, metric, "host-1") {
+		if id, ok := data["metric"].(map[string]string)["correlation_id"]; ok {
+			t.Fatalf("Unexpected correlation_id label %q", id)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Labels every series needs, the policy and the guard leave them alone
+var seriesIdentity = map[string]bool{"__name__": true, "job": true, "instance": true}
+
+// Labels dropped unless the policy includes or keeps them. Series that differ
+// only in one of them are summed when it is dropped: cmdline_hash splits the
+// additive process_* gauges of one process name and user by command line.
+var optInLabels = map[string]bool{"cmdline_hash": true}
+
+const (
+	// OverLimitStrip removes a label whose value would exceed the limit
+	OverLimitStrip = "strip"
+	// OverLimitReject drops the whole series
+	OverLimitReject = "reject"
+)
+
+// LabelPolicy decides which labels reach VictoriaMetrics
+type LabelPolicy struct {
+	// Drop removes these labels from every series
+	Drop []string
+	// Keep, when set, removes every label not listed
+	Keep []string
+	// Include adds opt-in labels, such as cmdline_hash
+	Include []string
+	// MaxValues caps the distinct values of a label per metric within Window, 0 disables the guard
+	MaxValues int
+	Window    time.Duration
+	// OverLimit is OverLimitStrip or OverLimitReject
+	OverLimit string
+}
+
+// DefaultLabelPolicy keeps every label and guards against unbounded ones
+func DefaultLabelPolicy() LabelPolicy {
+	return LabelPolicy{
+		MaxValues: 5000,
+		Window:    time.Hour,
+		OverLimit: OverLimitStrip,
+	}
+}
+
+// GetLabelPolicy returns the default policy with AGGREGATOR_LABELS_* overrides
+func GetLabelPolicy() (LabelPolicy, error) {
+	policy := DefaultLabelPolicy()
+
+	if v := os.Getenv("AGGREGATOR_LABELS_DROP"); v != "" {
+		policy.Drop = splitList(v)
+	}
+
+	if v := os.Getenv("AGGREGATOR_LABELS_KEEP"); v != "" {
+		policy.Keep = splitList(v)
+	}
+
+	if v := os.Getenv("AGGREGATOR_LABELS_INCLUDE"); v != "" {
+		policy.Include = splitList(v)
+	}
+
+	if v := os.Getenv("AGGREGATOR_LABELS_MAX_VALUES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return LabelPolicy{}, fmt.Errorf("could not parse AGGREGATOR_LABELS_MAX_VALUES: %w", err)
+		}
+		policy.MaxValues = n
+	}
+
+	if v := os.Getenv("AGGREGATOR_LABELS_WINDOW"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return LabelPolicy{}, fmt.Errorf("could not parse AGGREGATOR_LABELS_WINDOW: %w", err)
+		}
+		policy.Window = d
+	}
+
+	if v := os.Getenv("AGGREGATOR_LABELS_OVER_LIMIT"); v != "" {
+		policy.OverLimit = v
+	}
+
+	return policy, policy.Validate()
+}
+
+// Validate reports the first problem with the policy
+func (p LabelPolicy) Validate() error {
+	for _, name := range p.Include {
+		if !optInLabels[name] {
+			return fmt.Errorf("label %q is not opt-in", name)
+		}
+	}
+	if p.MaxValues < 0 {
+		return fmt.Errorf("label max values must not be negative, got %d", p.MaxValues)
+	}
+	if p.MaxValues > 0 && p.Window <= 0 {
+		return fmt.Errorf("label window must be positive, got %s", p.Window)
+	}
+	if p.OverLimit != OverLimitStrip && p.OverLimit != OverLimitReject {
+		return fmt.Errorf("label over limit action must be %q or %q, got %q", OverLimitStrip, OverLimitReject, p.OverLimit)
+	}
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+var labelsLimited = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gomon_aggregator_label_limit_total",
+		Help: "Labels stripped or series rejected because a label exceeded its distinct values limit",
+	},
+	[]string{"action"},
+)
+
+func init() {
+	prometheus.MustRegister(labelsLimited)
+}
+
+// LabelGuard applies a LabelPolicy to series before they are written.
+// It is safe for concurrent use by the workers.
+type LabelGuard struct {
+	policy  LabelPolicy
+	drop    map[string]bool
+	keep    map[string]bool
+	include map[string]bool
+	now     func() time.Time
+
+	mu      sync.Mutex
+	resetAt time.Time
+	// metric name -> label name -> values seen in the current window
+	seen map[string]map[string]*labelValues
+}
+
+type labelValues struct {
+	values map[string]struct{}
+	warned bool
+}
+
+// NewLabelGuard returns a guard enforcing policy
+func NewLabelGuard(policy LabelPolicy) *LabelGuard {
+	g := &LabelGuard{
+		policy:  policy,
+		drop:    make(map[string]bool),
+		include: make(map[string]bool),
+		now:     time.Now,
+		seen:    make(map[string]map[string]*labelValues),
+	}
+	for _, name := range policy.Drop {
+		g.drop[name] = true
+	}
+	for _, name := range policy.Include {
+		g.include[name] = true
+	}
+	for _, name := range policy.Keep {
+		g.include[name] = true
+	}
+	if len(policy.Keep) > 0 {
+		g.keep = make(map[string]bool)
+		for _, name := range policy.Keep {
+			g.keep[name] = true
+		}
+	}
+	return g
+}
+
+// Apply filters the labels of each series and drops rejected series
+func (g *LabelGuard) Apply(series []map[string]interface{}, logger *log.Logger) []map[string]interface{} {
+	kept := series[:0]
+	// Series that lost an opt-in label, by their remaining labels
+	merged := make(map[string]map[string]interface{})
+	for _, data := range series {
+		labels, ok := data["metric"].(map[string]string)
+		if !ok {
+			continue
+		}
+		optedOut := false
+		for name := range labels {
+			if seriesIdentity[name] {
+				continue
+			}
+			switch {
+			case optInLabels[name] && !g.include[name]:
+				optedOut = true
+				delete(labels, name)
+			case g.drop[name] || (g.keep != nil && !g.keep[name]):
+				delete(labels, name)
+			}
+		}
+
+		var key string
+		if optedOut {
+			key = labelsKey(labels)
+			if first, ok := merged[key]; ok {
+				addValues(first, data)
+				continue
+			}
+		}
+		if g.admit(labels, logger) {
+			kept = append(kept, data)
+			if optedOut {
+				merged[key] = data
+			}
+		}
+	}
+	return kept
+}
+
+// labelsKey identifies a label set
+func labelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var key strings.Builder
+	for _, name := range names {
+		key.WriteString(name)
+		key.WriteByte('=')
+		key.WriteString(labels[name])
+		key.WriteByte(0)
+	}
+	return key.String()
+}
+
+// addValues adds the values of series to the same samples of sum
+func addValues(sum, series map[string]interface{}) {
+	sums, _ := sum["values"].([]float64)
+	values, _ := series["values"].([]float64)
+	for i := range sums {
+		if i < len(values) {
+			sums[i] += values[i]
+		}
+	}
+}
+
+// admit records the label values of one series, stripping the labels over
+// the limit. It returns false when the series is rejected.
+func (g *LabelGuard) admit(labels map[string]string, logger *log.Logger) bool {
+	if g.policy.MaxValues == 0 {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Values are forgotten each window so rotating values (pods, devices) are not locked out
+	if now := g.now(); now.After(g.resetAt) {
+		g.seen = make(map[string]map[string]*labelValues)
+		g.resetAt = now.Add(g.policy.Window)
+	}
+
+	metric := labels["__name__"]
+	byLabel := g.seen[metric]
+	if byLabel == nil {
+		byLabel = make(map[string]*labelValues)
+		g.seen[metric] = byLabel
+	}
+
+	var over []string
+	for name, value := range labels {
+		if seriesIdentity[name] {
+			continue
+		}
+		seen := byLabel[name]
+		if seen == nil {
+			seen = &labelValues{values: make(map[string]struct{})}
+			byLabel[name] = seen
+		}
+		if _, ok := seen.values[value]; ok {
+			continue
+		}
+		if len(seen.values) < g.policy.MaxValues {
+			seen.values[value] = struct{}{}
+			continue
+		}
+		over = append(over, name)
+		if !seen.warned {
+			seen.warned = true
+			logger.Printf("WARNING: label %s of %s exceeds %d distinct values, applying %s",
+				name, metric, g.policy.MaxValues, g.policy.OverLimit)
+		}
+	}
+
+	if len(over) == 0 {
+		return true
+	}
+	if g.policy.OverLimit == OverLimitReject {
+		labelsLimited.WithLabelValues(OverLimitReject).Inc()
+		return false
+	}
+	for _, name := range over {
+		delete(labels, name)
+		labelsLimited.WithLabelValues(OverLimitStrip).Inc()
+	}
+	return true
+}
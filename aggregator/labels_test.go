@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabelPolicyDropAndKeep(t *testing.T) {
+
+	guard := NewLabelGuard(LabelPolicy{Drop: []string{"team"}, OverLimit: OverLimitStrip})
+	out := guard.Apply([]map[string]interface{}{testSeries("cpu_usage_percent", map[string]string{"team": "core", "env": "prod"})}, quietLogger)
+	labels := out[0]["metric"].(map[string]string)
+	if _, ok := labels["team"]; ok || labels["env"] != "prod" {
+		t.Errorf("Expected team dropped and env kept, got %v", labels)
+	}
+
+	guard = NewLabelGuard(LabelPolicy{Keep: []string{"mountpoint"}, OverLimit: OverLimitStrip})
+	out = guard.Apply([]map[string]interface{}{testSeries("disk_used_percent", map[string]string{"mountpoint": "/", "env": "prod"})}, quietLogger)
+	labels = out[0]["metric"].(map[string]string)
+	if len(labels) != 4 || labels["mountpoint"] != "/" || labels["instance"] != "node-a" {
+		t.Errorf("Expected only identity labels and mountpoint, got %v", labels)
+	}
+}
+
+func TestLabelPolicyOptIn(t *testing.T) {
+	series := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			testSeries("process_rss_bytes", map[string]string{"process": "java", "user": "app", "cmdline_hash": "aaaa"}),
+			testSeries("process_rss_bytes", map[string]string{"process": "java", "user": "app", "cmdline_hash": "bbbb"}),
+			testSeries("process_rss_bytes", map[string]string{"process": "sshd", "user": "root", "cmdline_hash": "cccc"}),
+		}
+	}
+
+	// cmdline_hash is dropped by default, the processes it told apart are summed
+	out := NewLabelGuard(DefaultLabelPolicy()).Apply(series(), quietLogger)
+	if len(out) != 2 {
+		t.Fatalf("Expected one series per process and user, got %d", len(out))
+	}
+	labels := out[0]["metric"].(map[string]string)
+	if _, ok := labels["cmdline_hash"]; ok || labels["process"] != "java" {
+		t.Errorf("Expected cmdline_hash dropped, got %v", labels)
+	}
+	if v := out[0]["values"].([]float64)[0]; v != 2 {
+		t.Errorf("Expected the java series summed, got %v", v)
+	}
+
+	policy := DefaultLabelPolicy()
+	policy.Include = []string{"cmdline_hash"}
+	if out := NewLabelGuard(policy).Apply(series(), quietLogger); len(out) != 3 || out[0]["metric"].(map[string]string)["cmdline_hash"] != "aaaa" {
+		t.Errorf("Expected cmdline_hash kept when included, got %d series", len(out))
+	}
+
+	policy.Include = []string{"pid"}
+	if err := policy.Validate(); err == nil {
+		t.Error("Expected error for including a label that is not opt-in")
+	}
+}
+
+func TestLabelGuardLimits(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	series := func(pod string) []map[string]interface{} {
+		return []map[string]interface{}{testSeries("container_cpu_usage_percent", map[string]string{"pod": pod, "env": "prod"})}
+	}
+
+	guard := NewLabelGuard(LabelPolicy{MaxValues: 2, Window: time.Hour, OverLimit: OverLimitStrip})
+	guard.now = func() time.Time { return now }
+	for _, pod := range []string{"a", "b", "a"} {
+		if out := guard.Apply(series(pod), quietLogger); out[0]["metric"].(map[string]string)["pod"] != pod {
+			t.Errorf("Expected pod %s within the limit to be kept", pod)
+		}
+	}
+	out := guard.Apply(series("c"), quietLogger)
+	if labels := out[0]["metric"].(map[string]string); labels["pod"] != "" || labels["env"] != "prod" {
+		t.Errorf("Expected only the pod label stripped over the limit, got %v", labels)
+	}
+
+	// Another metric has its own budget
+	other := []map[string]interface{}{testSeries("container_memory_usage_bytes", map[string]string{"pod": "c"})}
+	if out := guard.Apply(other, quietLogger); out[0]["metric"].(map[string]string)["pod"] != "c" {
+		t.Error("Expected the limit to be per metric")
+	}
+
+	// The next window starts from scratch
+	now = now.Add(2 * time.Hour)
+	if out := guard.Apply(series("c"), quietLogger); out[0]["metric"].(map[string]string)["pod"] != "c" {
+		t.Error("Expected values to be forgotten after the window")
+	}
+
+	guard = NewLabelGuard(LabelPolicy{MaxValues: 1, Window: time.Hour, OverLimit: OverLimitReject})
+	guard.Apply(series("a"), quietLogger)
+	if out := guard.Apply(append(series("b"), series("a")...), quietLogger); len(out) != 1 {
+		t.Errorf("Expected the series over the limit rejected, got %d series", len(out))
+	}
+}
+
+func TestGetLabelPolicy(t *testing.T) {
+	t.Setenv("AGGREGATOR_LABELS_DROP", "team, role")
+	t.Setenv("AGGREGATOR_LABELS_MAX_VALUES", "100")
+	t.Setenv("AGGREGATOR_LABELS_OVER_LIMIT", OverLimitReject)
+
+	policy, err := GetLabelPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Drop) != 2 || policy.Drop[1] != "role" || policy.MaxValues != 100 || policy.OverLimit != OverLimitReject {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+
+	t.Setenv("AGGREGATOR_LABELS_OVER_LIMIT", "truncate")
+	if _, err := GetLabelPolicy(); err == nil {
+		t.Error("Expected error for unknown over limit action")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"time"
+
+	"gomon/pb"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	stageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "gomon_pipeline_stage_duration_seconds",
+			Help:    "Time a payload spent in each pipeline stage, from the timestamps it carries",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16), // 1ms to ~33s
+		},
+		[]string{"stage"},
+	)
+	stageClockSkew = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_pipeline_clock_skew_total",
+			Help: "Stages skipped because their end preceded their start, usually clock skew between hosts",
+		},
+		[]string{"stage"},
+	)
+	payloadsBySchema = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_aggregator_payloads_total",
+			Help: "Payloads consumed by schema version, to follow the v1 to v2 migration",
+		},
+		[]string{"schema_version"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(stageDuration)
+	prometheus.MustRegister(stageClockSkew)
+	prometheus.MustRegister(payloadsBySchema)
+}
+
+// pipelineStages measures each leg between the timestamps of batch.
+// Stages with a missing timestamp are left out.
+func pipelineStages(batch *pb.MetricBatch) map[string]time.Duration {
+	started := batch.TraceStartTimeMs
+	published := batch.KafkaPublishTimeMs
+	received := batch.AggregatorReceivedTimeMs
+	written := batch.VmPublishTimeMs
+
+	stages := make(map[string]time.Duration)
+	between := func(stage string, from, to int64) {
+		if from > 0 && to > 0 {
+			stages[stage] = time.Duration(to-from) * time.Millisecond
+		}
+	}
+	between("collect", started, published)
+	between("kafka", published, received)
+	between("aggregate", received, written)
+	between("end_to_end", started, written)
+	return stages
+}
+
+// observePipelineLatency records the stage durations of a payload that reached VictoriaMetrics.
+// The trace and correlation ids go on the observations as exemplars, so a slow
+// bucket leads to the trace and log lines of one payload.
+func observePipelineLatency(batch *pb.MetricBatch, traceID string) {
+	exemplar := prometheus.Labels{}
+	if traceID != "" {
+		exemplar["trace_id"] = traceID
+	}
+	if batch.CorrelationId != "" {
+		exemplar["correlation_id"] = batch.CorrelationId
+	}
+
+	for stage, d := range pipelineStages(batch) {
+		if d < 0 {
+			stageClockSkew.WithLabelValues(stage).Inc()
+			continue
+		}
+		observer := stageDuration.WithLabelValues(stage)
+		if eo, ok := observer.(prometheus.ExemplarObserver); ok && len(exemplar) > 0 {
+			eo.ObserveWithExemplar(d.Seconds(), exemplar)
+			continue
+		}
+		observer.Observe(d.Seconds())
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"gomon/pb"
+	"gomon/schema"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestPipelineStages(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return start.Add(d).Format(time.RFC3339Nano) }
+
+	metric := &pb.Metric{
+		TraceStartTime:         at(0),
+		KafkaPublishTime:       at(2 * time.Second),
+		AggregatorReceivedTime: at(2500 * time.Millisecond),
+		VmPublishTime:          at(3 * time.Second),
+	}
+	// v1 timestamps reach the stages through the v2 conversion
+	stages := pipelineStages(schema.FromV1(metric))
+	want := map[string]time.Duration{
+		"collect":    2 * time.Second,
+		"kafka":      500 * time.Millisecond,
+		"aggregate":  500 * time.Millisecond,
+		"end_to_end": 3 * time.Second,
+	}
+	for stage, d := range want {
+		if stages[stage] != d {
+			t.Errorf("Stage %s: expected %v, got %v", stage, d, stages[stage])
+		}
+	}
+
+	// Payloads from older agents miss the publish time
+	metric.KafkaPublishTime = ""
+	stages = pipelineStages(schema.FromV1(metric))
+	if _, ok := stages["kafka"]; ok || len(stages) != 2 {
+		t.Errorf("Expected only aggregate and end_to_end, got %v", stages)
+	}
+}
+
+func TestPipelineLatencyExemplar(t *testing.T) {
+	batch := &pb.MetricBatch{
+		CorrelationId:            "corr-1",
+		TraceStartTimeMs:         1000,
+		KafkaPublishTimeMs:       2000,
+		AggregatorReceivedTimeMs: 2500,
+		VmPublishTimeMs:          3000,
+	}
+	observePipelineLatency(batch, "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "gomon_pipeline_stage_duration_seconds" {
+			continue
+		}
+		for _, m := range family.Metric {
+			for _, bucket := range m.GetHistogram().GetBucket() {
+				labels := map[string]string{}
+				for _, l := range bucket.GetExemplar().GetLabel() {
+					labels[l.GetName()] = l.GetValue()
+				}
+				if labels["correlation_id"] == "corr-1" && labels["trace_id"] == "4bf92f3577b34da6a3ce929d0e0e4736" {
+					return
+				}
+			}
+		}
+	}
+	t.Error("Expected an exemplar with the trace and correlation ids")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gomon/pb/prompb"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/proto"
+)
+
+// RemoteWriteConfig points the aggregator at a Prometheus remote write 1.0 receiver:
+// Prometheus, Mimir, Thanos Receive or VictoriaMetrics' /api/v1/write
+type RemoteWriteConfig struct {
+	URL string
+	// Headers sent with every request, e.g. X-Scope-OrgID for a Mimir tenant
+	Headers map[string]string
+	Batch   BatchConfig
+}
+
+// GetRemoteWriteConfig reads REMOTE_WRITE_URL, REMOTE_WRITE_HEADERS (name=value,...)
+// and the REMOTE_WRITE_* batching overrides
+func GetRemoteWriteConfig() (RemoteWriteConfig, error) {
+	cfg := RemoteWriteConfig{
+		URL:     os.Getenv("REMOTE_WRITE_URL"),
+		Headers: make(map[string]string),
+	}
+
+	if v := os.Getenv("REMOTE_WRITE_HEADERS"); v != "" {
+		for _, pair := range strings.Split(v, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return RemoteWriteConfig{}, fmt.Errorf("REMOTE_WRITE_HEADERS: %q is not name=value", pair)
+			}
+			cfg.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	batch, err := getBatchConfig("REMOTE_WRITE", DefaultBatchConfig())
+	if err != nil {
+		return RemoteWriteConfig{}, err
+	}
+	cfg.Batch = batch
+
+	if cfg.URL == "" {
+		return RemoteWriteConfig{}, fmt.Errorf("REMOTE_WRITE_URL environment variable is not set")
+	}
+	return cfg, nil
+}
+
+// remoteWrite sends batches as snappy compressed prompb.WriteRequest messages
+type remoteWrite struct {
+	cfg RemoteWriteConfig
+}
+
+func (r remoteWrite) name() string { return "remote-write" }
+
+// encode appends a WriteRequest per time series. Repeated fields
+// concatenate, so a batch of these is itself a valid WriteRequest.
+func (r remoteWrite) encode(buf *bytes.Buffer, payload *Payload) (int, error) {
+	for _, data := range payload.Series {
+		if err := r.encodeSeries(buf, data); err != nil {
+			return 0, err
+		}
+	}
+	return len(payload.Series), nil
+}
+
+func (r remoteWrite) encodeSeries(buf *bytes.Buffer, data map[string]interface{}) error {
+	labels, _ := data["metric"].(map[string]string)
+	values, _ := data["values"].([]float64)
+	timestamps, _ := data["timestamps"].([]int64)
+	if labels["__name__"] == "" || len(values) != len(timestamps) {
+		return fmt.Errorf("malformed series %v", labels)
+	}
+
+	series := &prompb.TimeSeries{}
+	for name, value := range labels {
+		// An empty value is no label to receivers, some reject it
+		if value == "" {
+			continue
+		}
+		series.Labels = append(series.Labels, &prompb.Label{Name: name, Value: value})
+	}
+	// Receivers require labels sorted by name
+	sort.Slice(series.Labels, func(i, j int) bool { return series.Labels[i].Name < series.Labels[j].Name })
+	for i, value := range values {
+		series.Samples = append(series.Samples, &prompb.Sample{Value: value, Timestamp: timestamps[i]})
+	}
+
+	request, err := proto.Marshal(&prompb.WriteRequest{Timeseries: []*prompb.TimeSeries{series}})
+	if err != nil {
+		return fmt.Errorf("could not marshal write request: %w", err)
+	}
+	buf.Write(request)
+	return nil
+}
+
+func (r remoteWrite) send(client *http.Client, batch []byte) error {
+	batch, err := mergeSeries(batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.cfg.URL, bytes.NewReader(snappy.Encode(nil, batch)))
+	if err != nil {
+		return fmt.Errorf("could not create HTTP request: %w", err)
+	}
+	for name, value := range r.cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	return doWrite(client, req)
+}
+
+// mergeSeries turns a batch into one time series per label set with its samples
+// in timestamp order. Workers queue messages concurrently, so a batch may hold
+// samples of one series from several messages out of order, which Prometheus
+// and Mimir reject. A sample older than one an earlier batch sent is still
+// rejected as out of order.
+func mergeSeries(batch []byte) ([]byte, error) {
+	request := &prompb.WriteRequest{}
+	if err := proto.Unmarshal(batch, request); err != nil {
+		return nil, fmt.Errorf("could not unmarshal write request: %w", err)
+	}
+
+	merged := &prompb.WriteRequest{}
+	byLabels := make(map[string]*prompb.TimeSeries)
+	var key strings.Builder
+	for _, series := range request.Timeseries {
+		key.Reset()
+		for _, label := range series.Labels {
+			key.WriteString(label.Name)
+			key.WriteByte('=')
+			key.WriteString(label.Value)
+			key.WriteByte(0)
+		}
+		if first, ok := byLabels[key.String()]; ok {
+			first.Samples = append(first.Samples, series.Samples...)
+			continue
+		}
+		byLabels[key.String()] = series
+		merged.Timeseries = append(merged.Timeseries, series)
+	}
+	for _, series := range merged.Timeseries {
+		sort.SliceStable(series.Samples, func(i, j int) bool { return series.Samples[i].Timestamp < series.Samples[j].Timestamp })
+	}
+
+	data, err := proto.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal write request: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"gomon/pb/prompb"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRemoteWriteOutput(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*prompb.WriteRequest
+		headers  []http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		compressed, _ := io.ReadAll(r.Body)
+		body, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			t.Errorf("Invalid snappy body: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req prompb.WriteRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Errorf("Invalid write request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, &req)
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	out := remoteWrite{cfg: RemoteWriteConfig{URL: server.URL + "/api/v1/push", Headers: map[string]string{"X-Scope-OrgID": "tenant-a"}}}
+	w := NewBatchWriter(DefaultBatchConfig(), out, server.Client(), quietLogger)
+
+	// Three messages end up in one request, the last one older and without a user
+	for _, series := range []map[string]interface{}{
+		testSeries("cpu_usage_percent", map[string]string{"env": "prod"}),
+		testSeries("disk_used_percent", map[string]string{"mountpoint": "/"}),
+		createSeries("cpu_usage_percent", 2, 1699999990000, "node-a", map[string]string{"env": "prod", "user": ""}),
+	} {
+		if err := w.Write(seriesPayload(series), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("Expected one batched request, got %d", len(requests))
+	}
+	h := headers[0]
+	if h.Get("Content-Encoding") != "snappy" || h.Get("Content-Type") != "application/x-protobuf" ||
+		h.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" || h.Get("X-Scope-OrgID") != "tenant-a" {
+		t.Errorf("Unexpected headers: %v", h)
+	}
+
+	series := requests[0].Timeseries
+	if len(series) != 2 {
+		t.Fatalf("Expected 2 time series, got %d", len(series))
+	}
+	var names []string
+	for _, l := range series[0].Labels {
+		names = append(names, l.Name+"="+l.Value)
+	}
+	want := "__name__=cpu_usage_percent,env=prod,instance=node-a,job=metrics-aggregator"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("Expected sorted labels %s, got %s", want, got)
+	}
+	if s := series[1].Samples; len(s) != 1 || s[0].Value != 1 || s[0].Timestamp != 1700000000000 {
+		t.Errorf("Unexpected samples: %v", s)
+	}
+	// Samples of one series are merged in timestamp order
+	if s := series[0].Samples; len(s) != 2 || s[0].Value != 2 || s[1].Timestamp != 1700000000000 {
+		t.Errorf("Expected the older sample first, got %v", s)
+	}
+}
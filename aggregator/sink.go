@@ -0,0 +1,257 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gomon/pb"
+)
+
+// Payload is one Kafka message on its way to the sinks
+type Payload struct {
+	// Raw is the message as the agent sent it, in schema Version
+	Raw     []byte
+	Version uint32
+	// Batch is the decoded message, v1 payloads converted to v2
+	Batch   *pb.MetricBatch
+	TraceID string
+	// Series are built from Batch and filtered by the label policy
+	Series []map[string]interface{}
+}
+
+// Sink is a metrics backend the aggregator writes payloads to
+type Sink interface {
+	// Name labels the sink's metrics, log lines and health
+	Name() string
+	// Write queues one payload. done is called once it is written or given
+	// up on; Write itself only fails on a bad payload or a closed sink.
+	Write(payload *Payload, done func(error)) error
+	// Health returns nil while the sink is delivering
+	Health() error
+	// Close flushes the queued series and stops the sink
+	Close()
+}
+
+// Sinks selectable with AGGREGATOR_SINKS
+const (
+	SinkVictoriaMetrics = "victoriametrics"
+	SinkRemoteWrite     = "remote-write"
+	SinkElasticsearch   = "elasticsearch"
+)
+
+// NewSinks builds the sinks listed in AGGREGATOR_SINKS, VictoriaMetrics imports by
+// default. AGGREGATOR_OUTPUT is still read when AGGREGATOR_SINKS is unset.
+func NewSinks(logger *log.Logger) (*FanOut, error) {
+	names := splitList(os.Getenv("AGGREGATOR_SINKS"))
+	if len(names) == 0 {
+		names = splitList(os.Getenv("AGGREGATOR_OUTPUT"))
+	}
+	if len(names) == 0 {
+		names = []string{SinkVictoriaMetrics}
+	}
+
+	fanOut := &FanOut{}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			fanOut.Close()
+			return nil, fmt.Errorf("sink %q is listed twice", name)
+		}
+		seen[name] = true
+
+		sink, err := newSink(name, logger)
+		if err != nil {
+			fanOut.Close()
+			return nil, fmt.Errorf("%s sink: %w", name, err)
+		}
+		fanOut.sinks = append(fanOut.sinks, sink)
+	}
+	return fanOut, nil
+}
+
+// newSink builds one sink with its own HTTP client, so a backend holding
+// connections open does not starve the others
+func newSink(name string, logger *log.Logger) (Sink, error) {
+	switch name {
+	case SinkVictoriaMetrics:
+		cfg, err := GetVMImportConfig()
+		if err != nil {
+			return nil, err
+		}
+		return NewBatchWriter(cfg.Batch, vmImport{cfg: cfg}, newSinkClient(), logger), nil
+	case SinkRemoteWrite:
+		cfg, err := GetRemoteWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		return NewBatchWriter(cfg.Batch, remoteWrite{cfg: cfg}, newSinkClient(), logger), nil
+	case SinkElasticsearch:
+		cfg, err := GetElasticsearchConfig()
+		if err != nil {
+			return nil, err
+		}
+		out, client := elasticsearch{cfg: cfg}, newSinkClient()
+		if err := out.setup(client); err != nil {
+			return nil, err
+		}
+		return NewBatchWriter(cfg.Batch, out, client, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown sink, expected %q, %q or %q", SinkVictoriaMetrics, SinkRemoteWrite, SinkElasticsearch)
+	}
+}
+
+func newSinkClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// FanOut writes every payload to several sinks. Each sink batches, queues and
+// retries on its own, so a slow backend only fills its own queue.
+type FanOut struct {
+	sinks []Sink
+}
+
+// NewFanOut returns a sink writing to all of sinks
+func NewFanOut(sinks ...Sink) *FanOut {
+	return &FanOut{sinks: sinks}
+}
+
+// Name lists the sinks
+func (f *FanOut) Name() string {
+	names := make([]string, len(f.sinks))
+	for i, sink := range f.sinks {
+		names[i] = sink.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Write hands the payload to every sink. done is called once all of them
+// reported, with the errors of the sinks that failed joined.
+func (f *FanOut) Write(payload *Payload, done func(error)) error {
+	if len(f.sinks) == 0 {
+		if done != nil {
+			done(nil)
+		}
+		return nil
+	}
+
+	var (
+		mu        sync.Mutex
+		remaining = len(f.sinks)
+		errs      []error
+	)
+	report := func(name string, err error) {
+		mu.Lock()
+		if err != nil {
+			errs = append(errs, &sinkError{sink: name, err: err})
+		}
+		remaining--
+		last := remaining == 0
+		mu.Unlock()
+		if last && done != nil {
+			done(errors.Join(errs...))
+		}
+	}
+
+	// Every sink copes with the series on its own: one failing does not stop the others
+	for _, sink := range f.sinks {
+		name := sink.Name()
+		if err := sink.Write(payload, func(err error) { report(name, err) }); err != nil {
+			report(name, err)
+		}
+	}
+	return nil
+}
+
+// Only returns a fan-out to the named sinks, for a replayed payload that failed
+// on some of them. A sink that is no longer configured fails every write.
+func (f *FanOut) Only(names []string) *FanOut {
+	only := &FanOut{}
+	for _, name := range names {
+		var found Sink = missingSink(name)
+		for _, sink := range f.sinks {
+			if sink.Name() == name {
+				found = sink
+				break
+			}
+		}
+		only.sinks = append(only.sinks, found)
+	}
+	return only
+}
+
+// Health joins the problems of the unhealthy sinks
+func (f *FanOut) Health() error {
+	var errs []error
+	for _, sink := range f.sinks {
+		if err := sink.Health(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes all sinks concurrently
+func (f *FanOut) Close() {
+	var wg sync.WaitGroup
+	for _, sink := range f.sinks {
+		wg.Add(1)
+		go func(sink Sink) {
+			defer wg.Done()
+			sink.Close()
+		}(sink)
+	}
+	wg.Wait()
+}
+
+// sinkError is the failure of one sink of a FanOut
+type sinkError struct {
+	sink string
+	err  error
+}
+
+func (e *sinkError) Error() string {
+	return e.sink + ": " + e.err.Error()
+}
+
+func (e *sinkError) Unwrap() error {
+	return e.err
+}
+
+// failedSinks names the sinks a FanOut error reports, nil for other errors
+func failedSinks(err error) []string {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var names []string
+	for _, err := range errs {
+		var failed *sinkError
+		if errors.As(err, &failed) {
+			names = append(names, failed.sink)
+		}
+	}
+	return names
+}
+
+// missingSink stands in for a sink a replayed payload names but that is not configured
+type missingSink string
+
+func (s missingSink) Name() string { return string(s) }
+func (s missingSink) Write(payload *Payload, done func(error)) error {
+	return fmt.Errorf("sink is not configured")
+}
+func (s missingSink) Health() error { return nil }
+func (s missingSink) Close()        {}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFanOutSlowSink(t *testing.T) {
+	fast := newVMImportServer(t)
+	received := make(chan struct{}, 10)
+	release := make(chan struct{})
+	slow := newStatusServer(t, func(int) int {
+		received <- struct{}{}
+		<-release
+		return http.StatusNoContent
+	})
+
+	fastBatch := BatchConfig{MaxSeries: 1, MaxBytes: 1 << 20, FlushInterval: time.Hour, QueueSize: 10}
+	slowBatch := fastBatch
+	slowBatch.QueueSize = 1
+	sink := NewFanOut(
+		NewBatchWriter(fastBatch, jsonImport(fast.URL), fast.Client(), quietLogger),
+		NewBatchWriter(slowBatch, remoteWrite{cfg: RemoteWriteConfig{URL: slow.URL}}, slow.Client(), quietLogger),
+	)
+
+	results := make(chan error, 5)
+	write := func() {
+		if err := sink.Write(seriesPayload(testSeries("a", nil)), func(err error) { results <- err }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The slow sink holds the first batch in flight and queues the second, the rest are dropped
+	write()
+	<-received
+	for i := 0; i < 4; i++ {
+		write()
+	}
+
+	deadline := time.After(2 * time.Second)
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-results:
+			if !errors.Is(err, errQueueFull) || !strings.Contains(err.Error(), "remote-write") {
+				t.Errorf("Expected the slow sink to drop, got %v", err)
+			}
+		case <-deadline:
+			t.Fatalf("Expected 3 series dropped by the slow sink, got %d", i)
+		}
+	}
+
+	// The fast sink was not held up
+	for {
+		bodies, _, _ := fast.requests()
+		if len(bodies) == 5 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("Expected 5 requests to the fast sink, got %d", len(bodies))
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	close(release)
+	sink.Close()
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("Unexpected error once the slow sink recovered: %v", err)
+		}
+	}
+}
+
+func TestNewSinks(t *testing.T) {
+
+	t.Setenv("AGGREGATOR_SINKS", "victoriametrics, remote-write")
+	t.Setenv("VICTORIA_METRICS_URL", "http://vm:8428/api/v1/import")
+	t.Setenv("REMOTE_WRITE_URL", "http://prometheus:9090/api/v1/write")
+	t.Setenv("REMOTE_WRITE_RETRIES", "5")
+	t.Setenv("REMOTE_WRITE_RETRY_BACKOFF", "2s")
+	sinks, err := NewSinks(quietLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sinks.Close()
+	if sinks.Name() != "victoriametrics,remote-write" {
+		t.Errorf("Expected both sinks, got %s", sinks.Name())
+	}
+	if cfg := sinks.sinks[1].(*BatchWriter).cfg; cfg.Retries != 5 || cfg.RetryBackoff != 2*time.Second {
+		t.Errorf("Expected remote write retry overrides, got %+v", cfg)
+	}
+
+	// AGGREGATOR_OUTPUT still selects a single sink
+	t.Setenv("AGGREGATOR_SINKS", "")
+	t.Setenv("AGGREGATOR_OUTPUT", SinkRemoteWrite)
+	sinks, err = NewSinks(quietLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sinks.Close()
+	if sinks.Name() != SinkRemoteWrite {
+		t.Errorf("Expected remote-write sink, got %s", sinks.Name())
+	}
+
+	t.Setenv("REMOTE_WRITE_URL", "")
+	if _, err := NewSinks(quietLogger); err == nil {
+		t.Error("Expected error without REMOTE_WRITE_URL")
+	}
+
+	for _, list := range []string{"influxdb", "victoriametrics,victoriametrics"} {
+		t.Setenv("AGGREGATOR_SINKS", list)
+		if _, err := NewSinks(quietLogger); err == nil {
+			t.Errorf("Expected error for AGGREGATOR_SINKS=%s", list)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormatJSON writes JSON lines to /api/v1/import
+	FormatJSON = "json"
+	// FormatPrometheus writes the text exposition format to /api/v1/import/prometheus
+	FormatPrometheus = "prometheus"
+)
+
+// VMImportConfig selects the VictoriaMetrics import endpoint and format
+type VMImportConfig struct {
+	URL    string
+	Format string
+	Gzip   bool
+	Batch  BatchConfig
+}
+
+// GetVMImportConfig reads VICTORIA_METRICS_URL, VM_IMPORT_FORMAT, VM_GZIP and the VM_* batching overrides
+func GetVMImportConfig() (VMImportConfig, error) {
+	cfg := VMImportConfig{
+		URL:    os.Getenv("VICTORIA_METRICS_URL"),
+		Format: FormatJSON,
+		Gzip:   true,
+	}
+
+	if v := os.Getenv("VM_IMPORT_FORMAT"); v != "" {
+		cfg.Format = v
+	}
+
+	if v := os.Getenv("VM_GZIP"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return VMImportConfig{}, fmt.Errorf("could not parse VM_GZIP: %w", err)
+		}
+		cfg.Gzip = enabled
+	}
+
+	batch, err := getBatchConfig("VM", DefaultBatchConfig())
+	if err != nil {
+		return VMImportConfig{}, err
+	}
+	cfg.Batch = batch
+
+	return cfg, cfg.Validate()
+}
+
+// Validate reports the first problem with the import settings
+func (c VMImportConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("VICTORIA_METRICS_URL environment variable is not set")
+	}
+	if c.Format != FormatJSON && c.Format != FormatPrometheus {
+		return fmt.Errorf("import format must be %q or %q, got %q", FormatJSON, FormatPrometheus, c.Format)
+	}
+	return nil
+}
+
+// importURL points a /api/v1/import URL at the endpoint of the configured format
+func (c VMImportConfig) importURL() string {
+	if c.Format == FormatPrometheus && strings.HasSuffix(c.URL, "/api/v1/import") {
+		return c.URL + "/prometheus"
+	}
+	return c.URL
+}
+
+// vmImport writes batches to the VictoriaMetrics import API
+type vmImport struct {
+	cfg VMImportConfig
+}
+
+func (v vmImport) name() string { return "victoriametrics" }
+
+func (v vmImport) encode(buf *bytes.Buffer, payload *Payload) (int, error) {
+	for _, data := range payload.Series {
+		if v.cfg.Format == FormatPrometheus {
+			if err := writePrometheusLines(buf, data); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		line, err := json.Marshal(data)
+		if err != nil {
+			return 0, fmt.Errorf("could not marshal JSON: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return len(payload.Series), nil
+}
+
+func (v vmImport) send(client *http.Client, batch []byte) error {
+	var body io.Reader = bytes.NewReader(batch)
+	if v.cfg.Gzip {
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(batch); err != nil {
+			return fmt.Errorf("could not compress batch: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("could not compress batch: %w", err)
+		}
+		body = &compressed
+	}
+
+	req, err := http.NewRequest(http.MethodPost, v.cfg.importURL(), body)
+	if err != nil {
+		return fmt.Errorf("could not create HTTP request: %w", err)
+	}
+	if v.cfg.Format == FormatJSON {
+		req.Header.Set("Content-Type", "application/stream+json")
+	} else {
+		req.Header.Set("Content-Type", "text/plain")
+	}
+	if v.cfg.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	return doWrite(client, req)
+}
+
+// doWrite sends a batch request and turns a non-2xx answer into a statusError
+func doWrite(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// checkStatus turns a non-2xx answer into a statusError
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	// The body names the offending line, which is all that is worth logging
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return &statusError{
+		code: resp.StatusCode,
+		msg:  fmt.Sprintf("unexpected response from %s: %s: %s", resp.Request.URL.Host, resp.Status, strings.TrimSpace(string(msg))),
+	}
+}
+
+// writePrometheusLines renders a series as `name{label="value"} value timestamp_ms` lines
+func writePrometheusLines(buf *bytes.Buffer, data map[string]interface{}) error {
+	labels, _ := data["metric"].(map[string]string)
+	values, _ := data["values"].([]float64)
+	timestamps, _ := data["timestamps"].([]int64)
+	if labels["__name__"] == "" || len(values) != len(timestamps) {
+		return fmt.Errorf("malformed series %v", labels)
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var series strings.Builder
+	series.WriteString(labels["__name__"])
+	if len(names) > 0 {
+		series.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				series.WriteByte(',')
+			}
+			series.WriteString(name)
+			series.WriteString(`="`)
+			series.WriteString(labelValueEscaper.Replace(labels[name]))
+			series.WriteByte('"')
+		}
+		series.WriteByte('}')
+	}
+
+	for i, value := range values {
+		buf.WriteString(series.String())
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.FormatInt(timestamps[i], 10))
+		buf.WriteByte('\n')
+	}
+	return nil
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
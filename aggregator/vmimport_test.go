@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBatchWriterPrometheusFormat(t *testing.T) {
+	server := newVMImportServer(t)
+	out := vmImport{cfg: VMImportConfig{URL: server.URL + "/api/v1/import", Format: FormatPrometheus}}
+	w := NewBatchWriter(DefaultBatchConfig(), out, server.Client(), quietLogger)
+	series := testSeries("disk_used_percent", map[string]string{"mountpoint": `C:\data "x"`})
+	series["values"] = []float64{12.5}
+	if err := w.Write(seriesPayload(series), nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	bodies, paths, encoding := server.requests()
+	if len(bodies) != 1 || paths[0] != "/api/v1/import/prometheus" || encoding[0] != "" {
+		t.Fatalf("Unexpected requests %v %v", paths, encoding)
+	}
+	want := `disk_used_percent{instance="node-a",job="metrics-aggregator",mountpoint="C:\\data \"x\""} 12.5 1700000000000` + "\n"
+	if bodies[0] != want {
+		t.Errorf("Expected %q, got %q", want, bodies[0])
+	}
+}
+
+func TestGetVMImportConfig(t *testing.T) {
+	t.Setenv("VICTORIA_METRICS_URL", "http://vm:8428/api/v1/import")
+	t.Setenv("VM_IMPORT_FORMAT", FormatPrometheus)
+	t.Setenv("VM_BATCH_SIZE", "100")
+	t.Setenv("VM_GZIP", "false")
+
+	cfg, err := GetVMImportConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Batch.MaxSeries != 100 || cfg.Gzip || cfg.importURL() != "http://vm:8428/api/v1/import/prometheus" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	t.Setenv("VM_IMPORT_FORMAT", "influx")
+	if _, err := GetVMImportConfig(); err == nil {
+		t.Error("Expected error for unknown import format")
+	}
+}
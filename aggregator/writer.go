@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BatchConfig bounds the batches of a BatchWriter. A batch is flushed when it holds
+// MaxSeries series or MaxBytes encoded bytes, or after FlushInterval.
+type BatchConfig struct {
+	MaxSeries     int
+	MaxBytes      int
+	FlushInterval time.Duration
+	// QueueSize full batches wait for the sender, later ones are dropped
+	QueueSize int
+	// Retries of a failed batch. The first waits RetryBackoff, each later one twice as
+	// long up to RetryMaxBackoff. Rejected batches (4xx) are not retried.
+	Retries         int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+}
+
+// DefaultBatchConfig returns the batching defaults shared by all sinks
+func DefaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		MaxSeries:       5000,
+		MaxBytes:        4 << 20,
+		FlushInterval:   time.Second,
+		QueueSize:       4,
+		Retries:         3,
+		RetryBackoff:    time.Second,
+		RetryMaxBackoff: 30 * time.Second,
+	}
+}
+
+// getBatchConfig applies <prefix>_BATCH_SIZE, _BATCH_BYTES, _FLUSH_INTERVAL,
+// _QUEUE_SIZE, _RETRIES, _RETRY_BACKOFF and _RETRY_MAX_BACKOFF to cfg
+func getBatchConfig(prefix string, cfg BatchConfig) (BatchConfig, error) {
+	ints := map[string]*int{
+		prefix + "_BATCH_SIZE":  &cfg.MaxSeries,
+		prefix + "_BATCH_BYTES": &cfg.MaxBytes,
+		prefix + "_QUEUE_SIZE":  &cfg.QueueSize,
+		prefix + "_RETRIES":     &cfg.Retries,
+	}
+	for name, field := range ints {
+		if v := os.Getenv(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return BatchConfig{}, fmt.Errorf("could not parse %s: %w", name, err)
+			}
+			*field = n
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		prefix + "_FLUSH_INTERVAL":    &cfg.FlushInterval,
+		prefix + "_RETRY_BACKOFF":     &cfg.RetryBackoff,
+		prefix + "_RETRY_MAX_BACKOFF": &cfg.RetryMaxBackoff,
+	}
+	for name, field := range durations {
+		if v := os.Getenv(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return BatchConfig{}, fmt.Errorf("could not parse %s: %w", name, err)
+			}
+			*field = d
+		}
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate reports the first problem with the batch limits
+func (c BatchConfig) Validate() error {
+	if c.MaxSeries <= 0 || c.MaxBytes <= 0 || c.FlushInterval <= 0 {
+		return fmt.Errorf("batch size, batch bytes and flush interval must be positive")
+	}
+	if c.QueueSize < 0 || c.Retries < 0 || c.RetryBackoff < 0 {
+		return fmt.Errorf("queue size, retries and retry backoff must not be negative")
+	}
+	if c.RetryMaxBackoff < c.RetryBackoff {
+		return fmt.Errorf("retry max backoff %s is below the retry backoff %s", c.RetryMaxBackoff, c.RetryBackoff)
+	}
+	return nil
+}
+
+// batchOutput is the wire format and endpoint a BatchWriter writes to
+type batchOutput interface {
+	// name labels the writer's metrics and log lines
+	name() string
+	// encode appends a payload to a batch body and returns the series or documents it added
+	encode(buf *bytes.Buffer, payload *Payload) (int, error)
+	// send writes one batch body
+	send(client *http.Client, body []byte) error
+}
+
+// errQueueFull is passed to the callbacks of series dropped by a full queue
+var errQueueFull = errors.New("sink queue is full")
+
+// A writer is unhealthy after this many batches failed in a row
+const unhealthyAfter = 3
+
+var (
+	sinkFlushes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_aggregator_sink_flushes_total",
+			Help: "Batches sent to a sink by result",
+		},
+		[]string{"sink", "result"},
+	)
+	sinkFlushDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "gomon_aggregator_sink_flush_duration_seconds",
+			Help:    "Duration of one batch write including retries",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"sink"},
+	)
+	sinkSeries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_aggregator_sink_series_total",
+			Help: "Series handled by a sink by result: success, error or dropped",
+		},
+		[]string{"sink", "result"},
+	)
+	sinkRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "gomon_aggregator_sink_retries_total",
+			Help: "Batch writes retried after a transient failure",
+		},
+		[]string{"sink"},
+	)
+	sinkBufferedBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gomon_aggregator_sink_buffered_bytes",
+			Help: "Encoded series waiting in the batch being filled",
+		},
+		[]string{"sink"},
+	)
+	sinkQueuedBatches = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gomon_aggregator_sink_queued_batches",
+			Help: "Full batches waiting for the sender",
+		},
+		[]string{"sink"},
+	)
+	sinkUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "gomon_aggregator_sink_up",
+			Help: "1 while the sink is healthy, 0 after repeated failed writes",
+		},
+		[]string{"sink"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(sinkFlushes)
+	prometheus.MustRegister(sinkFlushDuration)
+	prometheus.MustRegister(sinkSeries)
+	prometheus.MustRegister(sinkRetries)
+	prometheus.MustRegister(sinkBufferedBytes)
+	prometheus.MustRegister(sinkQueuedBatches)
+	prometheus.MustRegister(sinkUp)
+}
+
+// writeBatch is the encoded series of one request plus the callbacks of the messages in it
+type writeBatch struct {
+	body    bytes.Buffer
+	series  int
+	flushed []func(error)
+}
+
+func (b *writeBatch) done(err error) {
+	for _, flushed := range b.flushed {
+		flushed(err)
+	}
+}
+
+// BatchWriter is a Sink that buffers series across Kafka messages and writes them
+// to an output in batches from its own goroutine. Memory is bounded by MaxBytes
+// times the batch being filled, the QueueSize queued batches and the one in flight.
+// Once the queue is full new batches are dropped, so a slow backend never blocks
+// the workers or the other sinks.
+type BatchWriter struct {
+	cfg    BatchConfig
+	out    batchOutput
+	client *http.Client
+	logger *log.Logger
+
+	mu      sync.Mutex
+	current *writeBatch
+	closed  bool
+
+	queue chan *writeBatch
+	done  chan struct{}
+	// stop is closed by Close and cuts retry backoffs short
+	stop chan struct{}
+
+	healthMu sync.Mutex
+	failures int
+	lastErr  error
+}
+
+// NewBatchWriter starts the flush loop of a writer
+func NewBatchWriter(cfg BatchConfig, out batchOutput, client *http.Client, logger *log.Logger) *BatchWriter {
+	w := &BatchWriter{
+		cfg:     cfg,
+		out:     out,
+		client:  client,
+		logger:  logger,
+		current: &writeBatch{},
+		queue:   make(chan *writeBatch, cfg.QueueSize),
+		done:    make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+	sinkUp.WithLabelValues(out.name()).Set(1)
+	go w.run()
+	return w
+}
+
+// Name of the output the writer sends to
+func (w *BatchWriter) Name() string {
+	return w.out.name()
+}
+
+// Write adds one payload to the current batch. flushed is called once
+// the batch holding it has been written, or failed to.
+func (w *BatchWriter) Write(payload *Payload, flushed func(error)) error {
+	var encoded bytes.Buffer
+	series, err := w.out.encode(&encoded, payload)
+	if err != nil {
+		return err
+	}
+
+	var dropped []*writeBatch
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return fmt.Errorf("%s sink is closed", w.Name())
+	}
+
+	// Flush first rather than grow past MaxBytes, unless the message alone is larger
+	if w.current.series > 0 && w.current.body.Len()+encoded.Len() > w.cfg.MaxBytes {
+		dropped = append(dropped, w.enqueue()...)
+	}
+	w.current.body.Write(encoded.Bytes())
+	w.current.series += series
+	if flushed != nil {
+		w.current.flushed = append(w.current.flushed, flushed)
+	}
+	if w.current.series >= w.cfg.MaxSeries || w.current.body.Len() >= w.cfg.MaxBytes {
+		dropped = append(dropped, w.enqueue()...)
+	}
+	sinkBufferedBytes.WithLabelValues(w.Name()).Set(float64(w.current.body.Len()))
+	w.mu.Unlock()
+
+	// Callbacks run outside the lock, they may write again
+	for _, batch := range dropped {
+		w.logger.Printf("WARNING: %s queue is full, dropping %d series", w.Name(), batch.series)
+		sinkSeries.WithLabelValues(w.Name(), "dropped").Add(float64(batch.series))
+		batch.done(errQueueFull)
+	}
+	return nil
+}
+
+// enqueue passes the current batch to the flush loop and starts a new one. It
+// returns the batch instead when the queue is full. Called with w.mu held.
+func (w *BatchWriter) enqueue() []*writeBatch {
+	batch := w.current
+	w.current = &writeBatch{}
+	select {
+	case w.queue <- batch:
+		sinkQueuedBatches.WithLabelValues(w.Name()).Set(float64(len(w.queue)))
+		return nil
+	default:
+		return []*writeBatch{batch}
+	}
+}
+
+// Health reports repeated failed writes
+func (w *BatchWriter) Health() error {
+	w.healthMu.Lock()
+	defer w.healthMu.Unlock()
+	if w.failures >= unhealthyAfter {
+		return fmt.Errorf("%d batches failed in a row, last: %w", w.failures, w.lastErr)
+	}
+	return nil
+}
+
+// Close flushes the buffered series and stops the flush loop. Batches that fail
+// from then on are not retried, their callbacks get the error right away.
+func (w *BatchWriter) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	batch := w.current
+	w.current = &writeBatch{}
+	w.mu.Unlock()
+	close(w.stop)
+
+	// Wait for room rather than drop what is left at shutdown
+	if batch.series > 0 || len(batch.flushed) > 0 {
+		w.queue <- batch
+	}
+	close(w.queue)
+	<-w.done
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case batch, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			sinkQueuedBatches.WithLabelValues(w.Name()).Set(float64(len(w.queue)))
+			w.flush(batch)
+		case <-ticker.C:
+			w.mu.Lock()
+			batch := w.current
+			if w.closed || (batch.series == 0 && len(batch.flushed) == 0) {
+				w.mu.Unlock()
+				continue
+			}
+			w.current = &writeBatch{}
+			sinkBufferedBytes.WithLabelValues(w.Name()).Set(0)
+			w.mu.Unlock()
+			w.flush(batch)
+		}
+	}
+}
+
+func (w *BatchWriter) flush(batch *writeBatch) {
+	var err error
+	if batch.series > 0 {
+		sink := w.Name()
+		start := time.Now()
+		err = w.out.send(w.client, batch.body.Bytes())
+		attempts := 1
+		stopped := false
+		for ; err != nil && retryable(err) && attempts <= w.cfg.Retries; attempts++ {
+			delay := w.cfg.retryDelay(attempts)
+			if stopped = !w.wait(delay); stopped {
+				break
+			}
+			w.logger.Printf("Retrying %s write (%d/%d) after %s: %v", sink, attempts, w.cfg.Retries, delay, err)
+			sinkRetries.WithLabelValues(sink).Inc()
+			err = w.out.send(w.client, batch.body.Bytes())
+		}
+		switch {
+		case err != nil && stopped:
+			err = fmt.Errorf("sink closed, giving up after %d attempts: %w", attempts, err)
+		case err != nil && attempts > 1:
+			err = fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+		}
+		sinkFlushDuration.WithLabelValues(sink).Observe(time.Since(start).Seconds())
+
+		result := "success"
+		if err != nil {
+			result = "error"
+			w.logger.Printf("Error writing %d series (%d bytes) to %s: %v", batch.series, batch.body.Len(), sink, err)
+		} else {
+			w.logger.Printf("Wrote %d series (%d bytes) to %s", batch.series, batch.body.Len(), sink)
+		}
+		sinkFlushes.WithLabelValues(sink, result).Inc()
+		sinkSeries.WithLabelValues(sink, result).Add(float64(batch.series))
+		w.recordResult(err)
+	}
+
+	batch.done(err)
+}
+
+// wait sleeps for a retry backoff. It returns false without waiting out the
+// delay once the writer is closed, so shutdown is not held up by a failing backend.
+func (w *BatchWriter) wait(delay time.Duration) bool {
+	select {
+	case <-w.stop:
+		return false
+	default:
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.stop:
+		return false
+	}
+}
+
+// recordResult tracks consecutive failures for Health
+func (w *BatchWriter) recordResult(err error) {
+	w.healthMu.Lock()
+	defer w.healthMu.Unlock()
+	if err == nil {
+		w.failures = 0
+		w.lastErr = nil
+		sinkUp.WithLabelValues(w.Name()).Set(1)
+		return
+	}
+	w.failures++
+	w.lastErr = err
+	if w.failures >= unhealthyAfter {
+		sinkUp.WithLabelValues(w.Name()).Set(0)
+	}
+}
+
+// retryDelay is the wait before retry n (from 1): RetryBackoff doubled per
+// retry, capped at RetryMaxBackoff
+func (c BatchConfig) retryDelay(n int) time.Duration {
+	delay := c.RetryBackoff
+	for i := 1; i < n && delay < c.RetryMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, c.RetryMaxBackoff)
+}
+
+// statusError is a write the backend answered with a non-2xx status
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// retryable tells transient failures (network errors, 5xx, 429) from rejected batches
+func retryable(err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusTooManyRequests
+	}
+	return true
+}
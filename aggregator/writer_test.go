@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchWriterBatchesBySize(t *testing.T) {
+	server := newVMImportServer(t)
+	batch := BatchConfig{MaxSeries: 3, MaxBytes: 1 << 20, FlushInterval: time.Hour, QueueSize: 1}
+	out := vmImport{cfg: VMImportConfig{URL: server.URL + "/api/v1/import", Format: FormatJSON, Gzip: true}}
+	w := NewBatchWriter(batch, out, server.Client(), quietLogger)
+
+	var flushed []error
+	var mu sync.Mutex
+	done := func(err error) {
+		mu.Lock()
+		flushed = append(flushed, err)
+		mu.Unlock()
+	}
+
+	// Two messages of two series: the second fills the first batch, Close flushes nothing else
+	for i := 0; i < 2; i++ {
+		if err := w.Write(seriesPayload(testSeries("a", nil), testSeries("b", nil)), done); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write(seriesPayload(testSeries("c", nil)), done); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	bodies, paths, encoding := server.requests()
+	if len(bodies) != 2 {
+		t.Fatalf("Expected a full batch and the rest on Close, got %d requests", len(bodies))
+	}
+	if lines := strings.Count(bodies[0], "\n"); lines != 4 {
+		t.Errorf("Expected 4 JSON lines in the first batch, got %d:\n%s", lines, bodies[0])
+	}
+	if paths[0] != "/api/v1/import" || encoding[0] != "gzip" {
+		t.Errorf("Unexpected request %s with encoding %q", paths[0], encoding[0])
+	}
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.SplitN(bodies[1], "\n", 2)[0]), &line); err != nil {
+		t.Errorf("Expected a JSON line, got %q: %v", bodies[1], err)
+	}
+
+	if len(flushed) != 3 {
+		t.Fatalf("Expected every message to be acknowledged, got %d", len(flushed))
+	}
+	for _, err := range flushed {
+		if err != nil {
+			t.Errorf("Unexpected flush error: %v", err)
+		}
+	}
+
+	if err := w.Write(seriesPayload(testSeries("d", nil)), nil); err == nil {
+		t.Error("Expected error writing to a closed writer")
+	}
+}
+
+func TestBatchWriterFlushesOnInterval(t *testing.T) {
+	server := newVMImportServer(t)
+	batch := DefaultBatchConfig()
+	batch.FlushInterval = 10 * time.Millisecond
+	out := vmImport{cfg: VMImportConfig{URL: server.URL + "/api/v1/import", Format: FormatJSON, Gzip: true}}
+	w := NewBatchWriter(batch, out, server.Client(), quietLogger)
+	defer w.Close()
+
+	if err := writeAndWait(t, w, seriesPayload(testSeries("a", nil))); err != nil {
+		t.Errorf("Unexpected flush error: %v", err)
+	}
+}
+
+func TestBatchWriterReportsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "cannot parse line 1", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := NewBatchWriter(DefaultBatchConfig(), jsonImport(server.URL), server.Client(), quietLogger)
+	var flushErr error
+	w.Write(seriesPayload(testSeries("a", nil)), func(err error) { flushErr = err })
+	w.Close()
+
+	if flushErr == nil || !strings.Contains(flushErr.Error(), "cannot parse line 1") {
+		t.Errorf("Expected the VictoriaMetrics error, got %v", flushErr)
+	}
+}
+
+func TestBatchWriterRetries(t *testing.T) {
+	batch := DefaultBatchConfig()
+	batch.MaxSeries = 1
+	batch.RetryBackoff = time.Millisecond
+
+	// 503 is retried until the backend recovers
+	server := newStatusServer(t, func(attempt int) int {
+		if attempt <= 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusNoContent
+	})
+	w := NewBatchWriter(batch, jsonImport(server.URL), server.Client(), quietLogger)
+	if err := writeAndWait(t, w, seriesPayload(testSeries("a", nil))); err != nil || server.count() != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d", err, server.count())
+	}
+	w.Close()
+
+	// 400 is not, and repeated failures mark the sink unhealthy
+	server = newStatusServer(t, func(int) int { return http.StatusBadRequest })
+	w = NewBatchWriter(batch, jsonImport(server.URL), server.Client(), quietLogger)
+	defer w.Close()
+	for i := 1; i <= unhealthyAfter; i++ {
+		if err := w.Health(); err != nil {
+			t.Errorf("Expected a healthy sink after %d failures, got %v", i-1, err)
+		}
+		if err := writeAndWait(t, w, seriesPayload(testSeries("a", nil))); err == nil {
+			t.Fatal("Expected the rejected batch to fail")
+		}
+		if server.count() != i {
+			t.Errorf("Expected one attempt per rejected batch, got %d for %d batches", server.count(), i)
+		}
+	}
+	if err := w.Health(); err == nil {
+		t.Error("Expected an unhealthy sink after repeated failures")
+	}
+}
+
+func TestBatchWriterCloseDuringBackoff(t *testing.T) {
+	sent := make(chan struct{}, 10)
+	server := newStatusServer(t, func(int) int {
+		sent <- struct{}{}
+		return http.StatusServiceUnavailable
+	})
+
+	batch := DefaultBatchConfig()
+	batch.MaxSeries = 1
+	batch.RetryBackoff = time.Hour
+	batch.RetryMaxBackoff = time.Hour
+	w := NewBatchWriter(batch, jsonImport(server.URL), server.Client(), quietLogger)
+
+	flushed := make(chan error, 1)
+	if err := w.Write(seriesPayload(testSeries("a", nil)), func(err error) { flushed <- err }); err != nil {
+		t.Fatal(err)
+	}
+	<-sent
+
+	// The writer is waiting an hour before its first retry
+	closed := make(chan struct{})
+	go func() {
+		w.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close waited for the retry backoff")
+	}
+	if err := <-flushed; err == nil || !strings.Contains(err.Error(), "sink closed") {
+		t.Errorf("Expected the batch to fail on close, got %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("Expected no retry after close, got %d", len(sent))
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := BatchConfig{RetryBackoff: 100 * time.Millisecond, RetryMaxBackoff: time.Second}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, delay := range want {
+		if got := cfg.retryDelay(i + 1); got != delay {
+			t.Errorf("Retry %d: expected %s, got %s", i+1, delay, got)
+		}
+	}
+
+	t.Setenv("VM_RETRY_BACKOFF", "2s")
+	t.Setenv("VM_RETRY_MAX_BACKOFF", "1s")
+	if _, err := getBatchConfig("VM", DefaultBatchConfig()); err == nil {
+		t.Error("Expected error for a max backoff below the backoff")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"gomon/alerting/internal/repository"
 	"gomon/alerting/internal/server"
 	"gomon/alerting/internal/slack"
+	"gomon/tracing"
 )
 
 func main() {
@@ -36,6 +38,12 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	// Initialize database connection
 	db, err := database.NewConnection(cfg.Db)
 	if err != nil {
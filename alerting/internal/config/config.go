@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"os"
 
+	"gomon/tracing"
+
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Http  HttpConfig  `yaml:"http"`
-	Db    DbConfig    `yaml:"db"`
-	Slack SlackConfig `yaml:"slack"`
+	Http    HttpConfig     `yaml:"http"`
+	Db      DbConfig       `yaml:"db"`
+	Slack   SlackConfig    `yaml:"slack"`
+	Tracing tracing.Config `yaml:"tracing"`
 }
 
 type HttpConfig struct {
@@ -55,11 +58,18 @@ func Load() (Config, error) {
 		return Config{}, fmt.Errorf("could not read %s: %w", configPath, err)
 	}
 
-	var config Config
+	config := Config{Tracing: tracing.DefaultConfig("gomon-alerting")}
 	if err := yaml.Unmarshal(byteYaml, &config); err != nil {
 		return Config{}, fmt.Errorf("could not unmarshal config: %w", err)
 	}
 
+	if err := config.Tracing.ApplyEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := config.Tracing.Validate(); err != nil {
+		return Config{}, err
+	}
+
 	return config, nil
 }
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -15,8 +16,14 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// Handler spans are children of the request span started by the otelgin middleware
+var tracer = otel.Tracer("gomon/alerting/handlers")
+
 type AlertHandler struct {
 	repo             repository.AlertRepository
 	slackClient      *slack.Client
@@ -38,7 +45,13 @@ func (h *AlertHandler) Create(ctx *gin.Context) {
 	}
 
 	// Save to database
+	_, dbSpan := tracer.Start(ctx.Request.Context(), "store-alert")
 	response, err := h.repo.Create(request)
+	if err != nil {
+		dbSpan.RecordError(err)
+		dbSpan.SetStatus(codes.Error, "insert failed")
+	}
+	dbSpan.End()
 	if err != nil {
 		if isDatabaseConstraintError(err) {
 			ctx.JSON(400, gin.H{"error": "Invalid alert data", "details": err.Error()})
@@ -64,7 +77,7 @@ func (h *AlertHandler) Create(ctx *gin.Context) {
 
 	// Send to Slack
 	if h.slackClient != nil {
-		go h.sendSlackNotification(request, response, h.metrics)
+		go h.sendSlackNotification(context.WithoutCancel(ctx.Request.Context()), request, response, h.metrics)
 	}
 
 	ctx.JSON(201, response)
@@ -240,7 +253,7 @@ func isDatabaseConstraintError(err error) bool {
 		strings.Contains(errorStr, "violates")
 }
 
-func (h *AlertHandler) sendSlackNotification(request models.CreateAlertRequest, response models.CreateAlertResponse, metrics *metrics.Metrics) {
+func (h *AlertHandler) sendSlackNotification(ctx context.Context, request models.CreateAlertRequest, response models.CreateAlertResponse, metrics *metrics.Metrics) {
 	// Check if this severity should trigger Slack notification
 	if !utils.ShouldNotifySlack(request.Severity) {
 		log.Printf("⏭️  Skipping Slack notification for severity: %s", request.Severity)
@@ -271,9 +284,15 @@ func (h *AlertHandler) sendSlackNotification(request models.CreateAlertRequest,
 		response.CreatedAt,
 	)
 
+	_, span := tracer.Start(ctx, "slack-notify")
+	span.SetAttributes(attribute.String("slack.channel", channel))
+	defer span.End()
+
 	// Send to Slack (circuit breaker is inside SendMessageToChannel)
 	err := h.slackClient.SendMessageToChannel(message, channel)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "slack notification failed")
 		log.Printf("⚠️  Slack notification failed for alert %s: %v", response.ID, err)
 		h.metrics.IncSlackNotifications("failure")
 	} else {
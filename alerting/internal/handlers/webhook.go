@@ -12,6 +12,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // VictoriaMetrics webhook payload
@@ -71,13 +74,27 @@ func (h *WebhookHandler) HandleVMWebhook(c *gin.Context) {
 	})
 }
 
-func (h *WebhookHandler) processAlert(ctx context.Context, vmAlert VMAlert) error {
+func (h *WebhookHandler) processAlert(ctx context.Context, vmAlert VMAlert) (err error) {
 	alertName := vmAlert.Labels["alertname"]
 	severity := vmAlert.Labels["severity"]
 	if severity == "" {
 		severity = "P3" // Default
 	}
 
+	ctx, span := tracer.Start(ctx, "process-alert", trace.WithAttributes(
+		attribute.String("alert.name", alertName),
+		attribute.String("alert.severity", severity),
+		attribute.String("alert.status", vmAlert.Status),
+		attribute.String("alert.fingerprint", vmAlert.Fingerprint),
+	))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "alert processing failed")
+		}
+		span.End()
+	}()
+
 	description := vmAlert.Annotations["description"]
 
 	log.Printf("🔄 Processing: %s [%s] - %s", alertName, severity, vmAlert.Status)
@@ -142,13 +159,19 @@ func (h *WebhookHandler) processAlert(ctx context.Context, vmAlert VMAlert) erro
 
 		// Send Slack notification
 		if h.alertHandler.slackClient != nil {
+			slackCtx := context.WithoutCancel(ctx)
 			go func() {
+				_, slackSpan := tracer.Start(slackCtx, "slack-notify")
+				defer slackSpan.End()
+
 				message := fmt.Sprintf(
 					"🚨 *%s*\n*Severity:* %s\n*Description:* %s",
 					alertName, severity, description,
 				)
 				if err := h.alertHandler.slackClient.SendMessage(message); err != nil {
 					log.Printf("⚠️ Slack failed: %v", err)
+					slackSpan.RecordError(err)
+					slackSpan.SetStatus(codes.Error, "slack notification failed")
 				}
 			}()
 		}
@@ -7,6 +7,7 @@ import (
 	"gomon/alerting/internal/handlers"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -23,8 +24,12 @@ func New(alertHandler *handlers.AlertHandler, healthHandler *handlers.HealthHand
 
 	webhookHandler := handlers.NewWebhookHandler(alertHandler)
 
+	// One server span per request, continuing the caller's trace when it sent one
+	router := gin.Default()
+	router.Use(otelgin.Middleware("gomon-alerting"))
+
 	return &Server{
-		router:         gin.Default(),
+		router:         router,
 		alertHandler:   alertHandler,
 		webhookHandler: webhookHandler,
 		healthHandler:  healthHandler,
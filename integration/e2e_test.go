@@ -30,16 +30,8 @@ func TestE2E(t *testing.T) {
 	}
 
 	// Kafka Producer and Consumer
-	kafkaProducer := kafka.NewKafkaProducer(kafkaBrokers, kafkaTopic)
+	kafkaProducer, kafkaReader := newKafkaClients(t, kafkaBrokers, kafkaTopic)
 	defer kafkaProducer.Close()
-
-	kafkaReader := kfk.NewReader(kfk.ReaderConfig{
-		Brokers:     strings.Split(kafkaBrokers, ","),
-		GroupID:     fmt.Sprintf("e2e-test-%d", time.Now().Unix()),
-		Topic:       kafkaTopic,
-		StartOffset: kfk.FirstOffset,
-		MaxWait:     1 * time.Second,
-	})
 	defer kafkaReader.Close()
 
 	// Create test metric
@@ -95,16 +87,8 @@ func TestE2EEmptyMetric(t *testing.T) {
 		t.Skip("KAFKA_TOPIC not set, skipping test")
 	}
 
-	kafkaProducer := kafka.NewKafkaProducer(kafkaBrokers, kafkaTopic)
+	kafkaProducer, kafkaReader := newKafkaClients(t, kafkaBrokers, kafkaTopic)
 	defer kafkaProducer.Close()
-
-	kafkaReader := kfk.NewReader(kfk.ReaderConfig{
-		Brokers:     strings.Split(kafkaBrokers, ","),
-		GroupID:     fmt.Sprintf("e2e-test-%d", time.Now().Unix()),
-		Topic:       kafkaTopic,
-		StartOffset: kfk.FirstOffset,
-		MaxWait:     1 * time.Second,
-	})
 	defer kafkaReader.Close()
 
 	metric := &pb.Metric{
@@ -160,16 +144,8 @@ func TestE2EMultipleMessages(t *testing.T) {
 		t.Skip("KAFKA_TOPIC not set, skipping test")
 	}
 
-	kafkaProducer := kafka.NewKafkaProducer(kafkaBrokers, kafkaTopic)
+	kafkaProducer, kafkaReader := newKafkaClients(t, kafkaBrokers, kafkaTopic)
 	defer kafkaProducer.Close()
-
-	kafkaReader := kfk.NewReader(kfk.ReaderConfig{
-		Brokers:     strings.Split(kafkaBrokers, ","),
-		GroupID:     fmt.Sprintf("e2e-test-%d", time.Now().Unix()),
-		Topic:       kafkaTopic,
-		StartOffset: kfk.FirstOffset,
-		MaxWait:     1 * time.Second,
-	})
 	defer kafkaReader.Close()
 
 	// Send 3 messages rapidly
@@ -212,3 +188,36 @@ func TestE2EMultipleMessages(t *testing.T) {
 		t.Errorf("Expected 3 messages, found %d", foundCount)
 	}
 }
+
+// newKafkaClients connects with the same KAFKA_TLS_* and KAFKA_SASL_* settings
+// the agent and aggregator use
+func newKafkaClients(t *testing.T, kafkaBrokers, kafkaTopic string) (*kafka.KafkaProducer, *kfk.Reader) {
+	t.Helper()
+
+	security, err := kafka.GetSecurityConfig()
+	if err != nil {
+		t.Fatalf("Invalid Kafka security settings: %v", err)
+	}
+
+	producerCfg := kafka.DefaultProducerConfig()
+	producerCfg.Security = security
+	kafkaProducer, err := kafka.NewKafkaProducerWithConfig(kafkaBrokers, kafkaTopic, producerCfg)
+	if err != nil {
+		t.Fatalf("Failed to create Kafka producer: %v", err)
+	}
+
+	dialer, err := security.Dialer()
+	if err != nil {
+		t.Fatalf("Failed to build Kafka dialer: %v", err)
+	}
+	kafkaReader := kfk.NewReader(kfk.ReaderConfig{
+		Brokers:     strings.Split(kafkaBrokers, ","),
+		GroupID:     fmt.Sprintf("e2e-test-%d", time.Now().UnixNano()),
+		Topic:       kafkaTopic,
+		StartOffset: kfk.FirstOffset,
+		MaxWait:     1 * time.Second,
+		Dialer:      dialer,
+	})
+
+	return kafkaProducer, kafkaReader
+}
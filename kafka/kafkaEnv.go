@@ -3,6 +3,7 @@ package kafka
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 func GetKafkaBrokers() (string, error) {
@@ -21,3 +22,46 @@ func GetKafkaTopic() (string, error) {
 	return kafkaTopic, nil
 
 }
+
+// GetSecurityConfig reads TLS and SASL settings from KAFKA_TLS_* and KAFKA_SASL_*
+func GetSecurityConfig() (SecurityConfig, error) {
+	var c SecurityConfig
+	if err := c.ApplyEnv(); err != nil {
+		return SecurityConfig{}, err
+	}
+	return c, c.Validate()
+}
+
+// ApplyEnv overrides c with the KAFKA_TLS_* and KAFKA_SASL_* variables that are set
+func (c *SecurityConfig) ApplyEnv() error {
+	bools := map[string]*bool{
+		"KAFKA_TLS_ENABLED":              &c.TLS.Enabled,
+		"KAFKA_TLS_INSECURE_SKIP_VERIFY": &c.TLS.InsecureSkipVerify,
+	}
+	for name, field := range bools {
+		if v := os.Getenv(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("could not parse %s: %w", name, err)
+			}
+			*field = b
+		}
+	}
+
+	values := map[string]*string{
+		"KAFKA_TLS_CA_FILE":        &c.TLS.CAFile,
+		"KAFKA_TLS_CERT_FILE":      &c.TLS.CertFile,
+		"KAFKA_TLS_KEY_FILE":       &c.TLS.KeyFile,
+		"KAFKA_TLS_SERVER_NAME":    &c.TLS.ServerName,
+		"KAFKA_SASL_MECHANISM":     &c.SASL.Mechanism,
+		"KAFKA_SASL_USERNAME":      &c.SASL.Username,
+		"KAFKA_SASL_PASSWORD":      &c.SASL.Password,
+		"KAFKA_SASL_PASSWORD_FILE": &c.SASL.PasswordFile,
+	}
+	for name, field := range values {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+		}
+	}
+	return nil
+}
@@ -2,42 +2,184 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// ProducerConfig tunes the Kafka writer. Zero values keep the kafka-go defaults.
+type ProducerConfig struct {
+	// A batch is sent when it holds BatchSize messages or BatchBytes bytes,
+	// or when Linger has passed since its first message
+	BatchSize  int
+	BatchBytes int64
+	Linger     time.Duration
+
+	// Compression codec: "none", "gzip", "snappy", "lz4" or "zstd"
+	Compression string
+	// Acks is "all", "one" or "none"
+	Acks string
+
+	MaxAttempts  int
+	WriteTimeout time.Duration
+
+	// Async makes SendMessage return without waiting for the broker,
+	// OnDelivery then reports the outcome of every batch
+	Async      bool
+	OnDelivery func(messages []kafka.Message, err error)
+
+	// Key is set on every message so one host's metrics stay ordered within a
+	// partition, empty means the hostname
+	Key string
+
+	// TLS and SASL settings, the zero value is plaintext
+	Security SecurityConfig
+}
+
+// DefaultProducerConfig returns the settings used by NewKafkaProducer
+func DefaultProducerConfig() ProducerConfig {
+	return ProducerConfig{
+		BatchSize:   100,
+		Linger:      10 * time.Millisecond,
+		Compression: "snappy",
+		Acks:        "all",
+	}
+}
+
 type KafkaProducer struct {
 	Writer *kafka.Writer
+	key    []byte
 }
 
+// fallbackKey keys the messages of NewKafkaProducer when the hostname is unknown
+const fallbackKey = "gomon"
+
 func NewKafkaProducer(brokers string, topic string) *KafkaProducer {
+	cfg := DefaultProducerConfig()
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Printf("Could not determine hostname for message keys, using %q: %v", fallbackKey, err)
+		hostname = fallbackKey
+	}
+	cfg.Key = hostname
+
+	producer, err := NewKafkaProducerWithConfig(brokers, topic, cfg)
+	if err != nil {
+		// The defaults are always valid
+		panic(err)
+	}
+	return producer
+}
+
+// NewKafkaProducerWithConfig creates a producer with batching, compression, acks
+// and delivery settings from cfg
+func NewKafkaProducerWithConfig(brokers string, topic string, cfg ProducerConfig) (*KafkaProducer, error) {
 	// Split the brokers string into a slice of broker addresses
 	brokerList := strings.Split(brokers, ",")
 
-	log.Printf("Creating Kafka producer with brokers %v and topic %s", brokerList, topic)
+	compression, err := ParseCompression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	acks, err := ParseAcks(cfg.Acks)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := cfg.Security.Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	key := cfg.Key
+	if key == "" {
+		if key, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("could not determine hostname for message keys: %w", err)
+		}
+	}
+
+	log.Printf("Creating Kafka producer with brokers %v and topic %s (batch %d, linger %s, compression %s, acks %s, async %t, key %s, tls %t, sasl %q)",
+		brokerList, topic, cfg.BatchSize, cfg.Linger, cfg.Compression, cfg.Acks, cfg.Async, key,
+		cfg.Security.TLS.Enabled, cfg.Security.SASL.Mechanism)
 	return &KafkaProducer{
-		Writer: kafka.NewWriter(kafka.WriterConfig{
-			Brokers:  brokerList, // Pass the slice of broker addresses
-			Topic:    topic,
-			Balancer: &kafka.LeastBytes{},
-		}),
+		Writer: &kafka.Writer{
+			Addr:  kafka.TCP(brokerList...),
+			Topic: topic,
+			// Same key, same partition: per-host ordering
+			Balancer:     &kafka.Hash{},
+			BatchSize:    cfg.BatchSize,
+			BatchBytes:   cfg.BatchBytes,
+			BatchTimeout: cfg.Linger,
+			Compression:  compression,
+			RequiredAcks: acks,
+			MaxAttempts:  cfg.MaxAttempts,
+			WriteTimeout: cfg.WriteTimeout,
+			Async:        cfg.Async,
+			Completion:   cfg.OnDelivery,
+			Transport:    transport,
+		},
+		key: []byte(key),
+	}, nil
+}
+
+// ParseCompression maps a codec name to the kafka-go codec, "" and "none" disable compression
+func ParseCompression(name string) (kafka.Compression, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
 	}
+	return 0, fmt.Errorf("unknown kafka compression %q (want none, gzip, snappy, lz4 or zstd)", name)
+}
+
+// ParseAcks maps an acks level to kafka-go, "" means all
+func ParseAcks(level string) (kafka.RequiredAcks, error) {
+	switch strings.ToLower(level) {
+	case "", "all", "-1":
+		return kafka.RequireAll, nil
+	case "one", "1":
+		return kafka.RequireOne, nil
+	case "none", "0":
+		return kafka.RequireNone, nil
+	}
+	return 0, fmt.Errorf("unknown kafka acks %q (want all, one or none)", level)
 }
 
 func (kp *KafkaProducer) SendMessage(data []byte) error {
+	return kp.SendMessageWithContext(context.Background(), data)
+}
+
+// SendMessageWithContext is SendMessage carrying the context of the span in ctx
+// in the message headers, so consumers can continue the trace
+func (kp *KafkaProducer) SendMessageWithContext(ctx context.Context, data []byte) error {
 	msg := kafka.Message{
-		Key:   []byte(time.Now().String()), // optional key
-		Value: data,
+		Key:     kp.key,
+		Value:   data,
+		Headers: InjectSpan(ctx),
+		// Also tells OnDelivery when a failed payload was produced
+		Time: time.Now(),
 	}
-	err := kp.Writer.WriteMessages(context.Background(), msg)
+	err := kp.Writer.WriteMessages(ctx, msg)
 	if err != nil {
 		log.Printf("Failed to write message to Kafka: %v", err)
 		return err
 	}
-	log.Println("Message sent to Kafka...")
+	if kp.Writer.Async {
+		log.Println("Message queued for Kafka...")
+	} else {
+		log.Println("Message sent to Kafka...")
+	}
 	return nil
 }
 
@@ -0,0 +1,59 @@
+package kafka
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestNewKafkaProducerWithConfig(t *testing.T) {
+	cfg := ProducerConfig{
+		BatchSize:   50,
+		Linger:      5 * time.Millisecond,
+		Compression: "zstd",
+		Acks:        "one",
+		Async:       true,
+	}
+
+	producer, err := NewKafkaProducerWithConfig("broker-a:9092,broker-b:9092", "metrics", cfg)
+	if err != nil {
+		t.Fatalf("Failed to create producer: %v", err)
+	}
+	defer producer.Close()
+
+	w := producer.Writer
+	if w.BatchSize != 50 || w.BatchTimeout != 5*time.Millisecond || !w.Async {
+		t.Errorf("Batching settings not applied: %+v", w)
+	}
+	if w.Compression != kafka.Zstd || w.RequiredAcks != kafka.RequireOne {
+		t.Errorf("Expected zstd and acks=one, got %v and %v", w.Compression, w.RequiredAcks)
+	}
+	if _, ok := w.Balancer.(*kafka.Hash); !ok {
+		t.Errorf("Expected key hash balancer, got %T", w.Balancer)
+	}
+
+	hostname, _ := os.Hostname()
+	if string(producer.key) != hostname {
+		t.Errorf("Expected hostname key %q, got %q", hostname, producer.key)
+	}
+}
+
+func TestProducerConfigValidation(t *testing.T) {
+	if _, err := NewKafkaProducerWithConfig("localhost:9092", "metrics", ProducerConfig{Compression: "brotli"}); err == nil {
+		t.Error("Expected error for unknown compression")
+	}
+	if _, err := NewKafkaProducerWithConfig("localhost:9092", "metrics", ProducerConfig{Acks: "two"}); err == nil {
+		t.Error("Expected error for unknown acks level")
+	}
+
+	producer, err := NewKafkaProducerWithConfig("localhost:9092", "metrics", ProducerConfig{Key: "node-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+	if string(producer.key) != "node-1" || producer.Writer.Compression != 0 {
+		t.Errorf("Expected explicit key and no compression, got %q and %v", producer.key, producer.Writer.Compression)
+	}
+}
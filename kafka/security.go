@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SecurityConfig describes how clients authenticate to the brokers.
+// The zero value connects in plaintext without authentication.
+type SecurityConfig struct {
+	TLS  TLSConfig  `yaml:"tls"`
+	SASL SASLConfig `yaml:"sasl"`
+}
+
+type TLSConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PEM CA bundle, empty means the system roots
+	CAFile string `yaml:"ca_file"`
+	// Client certificate for mutual TLS
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+type SASLConfig struct {
+	// Mechanism is "plain", "scram-sha-256" or "scram-sha-512", empty disables SASL
+	Mechanism string `yaml:"mechanism"`
+	Username  string `yaml:"username"`
+	// Password is only taken from the environment, files are preferred
+	Password     string `yaml:"-"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+// Validate reports incomplete TLS or SASL settings without touching any file
+func (c SecurityConfig) Validate() error {
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return errors.New("kafka tls: cert_file and key_file must be set together")
+	}
+	if !c.TLS.Enabled && (c.TLS.CAFile != "" || c.TLS.CertFile != "") {
+		return errors.New("kafka tls: certificates are configured but tls is not enabled")
+	}
+
+	switch strings.ToLower(c.SASL.Mechanism) {
+	case "":
+		return nil
+	case "plain", "scram-sha-256", "scram-sha-512":
+	default:
+		return fmt.Errorf("kafka sasl: unknown mechanism %q (want plain, scram-sha-256 or scram-sha-512)", c.SASL.Mechanism)
+	}
+	if c.SASL.Username == "" {
+		return errors.New("kafka sasl: username must be set")
+	}
+	if c.SASL.Password == "" && c.SASL.PasswordFile == "" {
+		return errors.New("kafka sasl: password or password_file must be set")
+	}
+	return nil
+}
+
+// BuildTLS loads the certificates, it returns nil when TLS is disabled
+func (c SecurityConfig) BuildTLS() (*tls.Config, error) {
+	if !c.TLS.Enabled {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.TLS.ServerName,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+	}
+
+	if c.TLS.CAFile != "" {
+		pem, err := os.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("kafka tls: could not read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("kafka tls: no certificates found in %s", c.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("kafka tls: could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// BuildSASL returns the SASL mechanism, or nil when SASL is disabled
+func (c SecurityConfig) BuildSASL() (sasl.Mechanism, error) {
+	mechanism := strings.ToLower(c.SASL.Mechanism)
+	if mechanism == "" {
+		return nil, nil
+	}
+
+	password := c.SASL.Password
+	if c.SASL.PasswordFile != "" {
+		data, err := os.ReadFile(c.SASL.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("kafka sasl: could not read password file: %w", err)
+		}
+		password = strings.TrimSpace(string(data))
+	}
+
+	switch mechanism {
+	case "plain":
+		return plain.Mechanism{Username: c.SASL.Username, Password: password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, c.SASL.Username, password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, c.SASL.Username, password)
+	}
+	return nil, fmt.Errorf("kafka sasl: unknown mechanism %q", c.SASL.Mechanism)
+}
+
+// Dialer builds the dialer readers use to reach the brokers
+func (c SecurityConfig) Dialer() (*kafka.Dialer, error) {
+	tlsConfig, mechanism, err := c.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		TLS:           tlsConfig,
+		SASLMechanism: mechanism,
+	}, nil
+}
+
+// Transport builds the transport writers use to reach the brokers
+func (c SecurityConfig) Transport() (*kafka.Transport, error) {
+	tlsConfig, mechanism, err := c.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Transport{
+		Dial: (&net.Dialer{Timeout: 10 * time.Second}).DialContext,
+		TLS:  tlsConfig,
+		SASL: mechanism,
+	}, nil
+}
+
+func (c SecurityConfig) build() (*tls.Config, sasl.Mechanism, error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+	tlsConfig, err := c.BuildTLS()
+	if err != nil {
+		return nil, nil, err
+	}
+	mechanism, err := c.BuildSASL()
+	if err != nil {
+		return nil, nil, err
+	}
+	return tlsConfig, mechanism, nil
+}
@@ -0,0 +1,119 @@
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertPair writes a self-signed certificate and its key as PEM files
+func writeCertPair(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gomon-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestSecurityFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertPair(t, dir)
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KAFKA_TLS_ENABLED", "true")
+	t.Setenv("KAFKA_TLS_CA_FILE", certFile)
+	t.Setenv("KAFKA_TLS_CERT_FILE", certFile)
+	t.Setenv("KAFKA_TLS_KEY_FILE", keyFile)
+	t.Setenv("KAFKA_SASL_MECHANISM", "SCRAM-SHA-512")
+	t.Setenv("KAFKA_SASL_USERNAME", "agent")
+	t.Setenv("KAFKA_SASL_PASSWORD_FILE", passwordFile)
+
+	security, err := GetSecurityConfig()
+	if err != nil {
+		t.Fatalf("GetSecurityConfig failed: %v", err)
+	}
+
+	tlsConfig, err := security.BuildTLS()
+	if err != nil {
+		t.Fatalf("BuildTLS failed: %v", err)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 {
+		t.Errorf("Expected CA pool and client certificate, got %+v", tlsConfig)
+	}
+
+	dialer, err := security.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer failed: %v", err)
+	}
+	if dialer.TLS == nil || dialer.SASLMechanism == nil || dialer.SASLMechanism.Name() != "SCRAM-SHA-512" {
+		t.Errorf("Dialer missing TLS or SCRAM: %+v", dialer)
+	}
+
+	transport, err := security.Transport()
+	if err != nil {
+		t.Fatalf("Transport failed: %v", err)
+	}
+	if transport.TLS == nil || transport.SASL == nil {
+		t.Errorf("Transport missing TLS or SASL: %+v", transport)
+	}
+}
+
+func TestSecurityValidation(t *testing.T) {
+	cases := map[string]SecurityConfig{
+		"cert without key":    {TLS: TLSConfig{Enabled: true, CertFile: "client.crt"}},
+		"ca without tls":      {TLS: TLSConfig{CAFile: "ca.crt"}},
+		"unknown mechanism":   {SASL: SASLConfig{Mechanism: "gssapi", Username: "u", Password: "p"}},
+		"missing username":    {SASL: SASLConfig{Mechanism: "plain", Password: "p"}},
+		"missing password":    {SASL: SASLConfig{Mechanism: "scram-sha-256", Username: "u"}},
+		"missing ca file":     {TLS: TLSConfig{Enabled: true, CAFile: "/does/not/exist"}},
+		"missing secret file": {SASL: SASLConfig{Mechanism: "plain", Username: "u", PasswordFile: "/does/not/exist"}},
+	}
+	for name, security := range cases {
+		if _, err := security.Dialer(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// Plaintext stays the default
+	dialer, err := SecurityConfig{}.Dialer()
+	if err != nil || dialer.TLS != nil || dialer.SASLMechanism != nil {
+		t.Errorf("Expected plaintext dialer, got %+v, %v", dialer, err)
+	}
+}
@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeadersCarrier lets a propagator read and write trace context in Kafka message headers
+type HeadersCarrier []kafka.Header
+
+var _ propagation.TextMapCarrier = (*HeadersCarrier)(nil)
+
+// Get returns the value of the first header with key
+func (c *HeadersCarrier) Get(key string) string {
+	for _, h := range *c {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces an existing header with the same key
+func (c *HeadersCarrier) Set(key, val string) {
+	for i := range *c {
+		if (*c)[i].Key == key {
+			(*c)[i].Value = []byte(val)
+			return
+		}
+	}
+	*c = append(*c, kafka.Header{Key: key, Value: []byte(val)})
+}
+
+// Keys lists the header keys
+func (c *HeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c))
+	for _, h := range *c {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// InjectSpan returns headers carrying the trace context of ctx, nil when there is none
+func InjectSpan(ctx context.Context) []kafka.Header {
+	var carrier HeadersCarrier
+	otel.GetTextMapPropagator().Inject(ctx, &carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// ExtractContext returns ctx carrying the remote span context the producer injected
+// into headers. Messages sent without one leave ctx unchanged.
+func ExtractContext(ctx context.Context, headers []kafka.Header) context.Context {
+	carrier := HeadersCarrier(headers)
+	return otel.GetTextMapPropagator().Extract(ctx, &carrier)
+}
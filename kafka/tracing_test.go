@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func useTestPropagator(t *testing.T) {
+	t.Helper()
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+}
+
+func TestSpanContextRoundTrip(t *testing.T) {
+	useTestPropagator(t)
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "kafka-publish")
+	headers := InjectSpan(ctx)
+	span.End()
+	if len(headers) == 0 {
+		t.Fatal("Expected trace headers")
+	}
+
+	_, child := tracer.Start(ExtractContext(context.Background(), headers), "gomon-aggregator-processing")
+	child.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(ended))
+	}
+	parent, consumer := ended[0], ended[1]
+	if consumer.Parent().SpanID() != parent.SpanContext().SpanID() ||
+		consumer.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("Expected child of trace %s span %s, got trace %s parent %s",
+			parent.SpanContext().TraceID(), parent.SpanContext().SpanID(),
+			consumer.SpanContext().TraceID(), consumer.Parent().SpanID())
+	}
+	if !consumer.Parent().IsRemote() {
+		t.Error("Expected the extracted parent to be remote")
+	}
+}
+
+func TestExtractWithoutHeaders(t *testing.T) {
+	useTestPropagator(t)
+	if headers := InjectSpan(context.Background()); headers != nil {
+		t.Errorf("Expected no headers without a span, got %v", headers)
+	}
+	if sc := trace.SpanContextFromContext(ExtractContext(context.Background(), nil)); sc.IsValid() {
+		t.Errorf("Expected no span context, got %v", sc)
+	}
+}
+
+func TestHeadersCarrierReplacesKey(t *testing.T) {
+	var carrier HeadersCarrier
+	carrier.Set("traceparent", "a")
+	carrier.Set("traceparent", "b")
+	if len(carrier) != 1 || carrier.Get("traceparent") != "b" {
+		t.Errorf("Expected a single replaced header, got %v", carrier)
+	}
+}
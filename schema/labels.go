@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Series labels the aggregator sets itself, host labels cannot replace them
+var reservedLabels = map[string]bool{
+	"__name__":       true,
+	"job":            true,
+	"instance":       true,
+	"correlation_id": true,
+}
+
+var labelNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateHostLabel reports whether name can be used as a host label
+func ValidateHostLabel(name string) error {
+	if !labelNameRe.MatchString(name) {
+		return fmt.Errorf("invalid label name %q", name)
+	}
+	if reservedLabels[name] || strings.HasPrefix(name, "__") {
+		return fmt.Errorf("label name %q is reserved", name)
+	}
+	return nil
+}
+
+// SanitizeLabelName turns a Kubernetes or cloud key such as
+// topology.kubernetes.io/zone into a valid label name
+func SanitizeLabelName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+// Package schema tells the metrics payload versions apart and converts between them
+package schema
+
+import (
+	"fmt"
+
+	"gomon/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// V1 is pb.Metric with its fixed CPU, memory, disk and network fields
+	V1 uint32 = 1
+	// V2 is pb.MetricBatch, a batch of generic labelled samples
+	V2 uint32 = 2
+)
+
+// Version reads the schema version of an encoded payload without decoding it fully.
+// Payloads from agents that predate versioning report V1.
+func Version(data []byte) (uint32, error) {
+	var envelope pb.SchemaVersion
+	// Fields of the actual message are unknown to the envelope and skipped
+	if err := proto.Unmarshal(data, &envelope); err != nil {
+		return 0, fmt.Errorf("could not read schema version: %w", err)
+	}
+	switch envelope.SchemaVersion {
+	case 0, V1:
+		return V1, nil
+	case V2:
+		return V2, nil
+	}
+	return 0, fmt.Errorf("unsupported schema version %d", envelope.SchemaVersion)
+}
+
+// Decode returns the payload as a v2 batch, converting v1 payloads
+func Decode(data []byte) (*pb.MetricBatch, uint32, error) {
+	version, err := Version(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if version == V1 {
+		var metric pb.Metric
+		if err := proto.Unmarshal(data, &metric); err != nil {
+			return nil, version, fmt.Errorf("could not unmarshal v1 payload: %w", err)
+		}
+		return FromV1(&metric), version, nil
+	}
+
+	var batch pb.MetricBatch
+	if err := proto.Unmarshal(data, &batch); err != nil {
+		return nil, version, fmt.Errorf("could not unmarshal v2 payload: %w", err)
+	}
+	return &batch, version, nil
+}
+
+// Encode marshals metric as a payload of the given schema version
+func Encode(metric *pb.Metric, version uint32) ([]byte, error) {
+	switch version {
+	case V1:
+		metric.SchemaVersion = V1
+		return proto.Marshal(metric)
+	case V2:
+		return proto.Marshal(FromV1(metric))
+	}
+	return nil, fmt.Errorf("unsupported schema version %d", version)
+}
@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"testing"
+
+	"gomon/pb"
+	"gomon/testutils"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVersion(t *testing.T) {
+	legacy, err := proto.Marshal(testutils.CreateMetric())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Version(legacy); err != nil || v != V1 {
+		t.Errorf("Expected unversioned payload to be V1, got %d (%v)", v, err)
+	}
+
+	v2, err := Encode(testutils.CreateMetric(), V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Version(v2); err != nil || v != V2 {
+		t.Errorf("Expected V2, got %d (%v)", v, err)
+	}
+
+	future, _ := proto.Marshal(&pb.SchemaVersion{SchemaVersion: 3})
+	if _, err := Version(future); err == nil {
+		t.Error("Expected error for unknown schema version")
+	}
+}
+
+func TestDecodeV1AndV2Agree(t *testing.T) {
+	metric := testutils.CreateMetric()
+	metric.Hostname = "node-a"
+	metric.Host = &pb.HostIdentity{MachineId: "abc123"}
+
+	v1, err := Encode(proto.Clone(metric).(*pb.Metric), V1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := Encode(proto.Clone(metric).(*pb.Metric), V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromV1, version, err := Decode(v1)
+	if err != nil || version != V1 {
+		t.Fatalf("Decode v1: version %d, err %v", version, err)
+	}
+	fromV2, version, err := Decode(v2)
+	if err != nil || version != V2 {
+		t.Fatalf("Decode v2: version %d, err %v", version, err)
+	}
+
+	if len(fromV1.Samples) == 0 {
+		t.Fatal("Expected samples converted from v1")
+	}
+	if !proto.Equal(fromV1, fromV2) {
+		t.Errorf("v1 and v2 payloads of the same metric decode differently:\n%v\n%v", fromV1, fromV2)
+	}
+	if fromV2.Hostname != "node-a" || fromV2.GetHost().GetMachineId() != "abc123" {
+		t.Errorf("Identity lost in v2 payload: %q %v", fromV2.Hostname, fromV2.Host)
+	}
+}
+
+func TestFromV1Samples(t *testing.T) {
+	metric := testutils.CreateMetric()
+	batch := FromV1(metric)
+
+	byName := map[string]*pb.Sample{}
+	for _, s := range batch.Samples {
+		if s.Name == "disk_used_percent" && s.Labels["mountpoint"] == "" {
+			t.Errorf("Disk sample without mountpoint label: %v", s)
+		}
+		byName[s.Name] = s
+	}
+
+	cpu := byName["cpu_usage_percent"]
+	if cpu == nil || cpu.Type != pb.Sample_GAUGE || cpu.Unit != UnitPercent {
+		t.Fatalf("Unexpected cpu sample: %v", cpu)
+	}
+	if cpu.Value != float64(metric.CpuUsagePercent) {
+		t.Errorf("Expected cpu %v, got %v", metric.CpuUsagePercent, cpu.Value)
+	}
+	if cpu.TimestampMs%1000 != 0 || cpu.TimestampMs == 0 {
+		t.Errorf("Expected v1 second timestamp in milliseconds, got %d", cpu.TimestampMs)
+	}
+}
+
+// An aggregator that only knows v1 reads a v2 payload as an empty Metric
+// instead of misreading its fields.
+func TestV2IsOpaqueToV1Consumers(t *testing.T) {
+	data, err := Encode(testutils.CreateMetric(), V2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var metric pb.Metric
+	if err := proto.Unmarshal(data, &metric); err != nil {
+		t.Fatal(err)
+	}
+	if metric.CpuUsagePercent != 0 || metric.Hostname != "" || len(metric.DiskStats) != 0 || metric.Timestamp != "" {
+		t.Errorf("Expected v1 fields to stay empty, got %v", &metric)
+	}
+	if metric.SchemaVersion != V2 {
+		t.Errorf("Expected the schema version to be readable, got %d", metric.SchemaVersion)
+	}
+}
+
+func TestHostLabelNames(t *testing.T) {
+	for name, want := range map[string]string{
+		"topology.kubernetes.io/zone": "topology_kubernetes_io_zone",
+		"team":                        "team",
+		"9lives":                      "_9lives",
+	} {
+		if got := SanitizeLabelName(name); got != want {
+			t.Errorf("SanitizeLabelName(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for _, name := range []string{"instance", "job", "__name__", "__meta", "a-b", ""} {
+		if ValidateHostLabel(name) == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+	if err := ValidateHostLabel("env"); err != nil {
+		t.Errorf("Expected env to be valid: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package schema
+
+import (
+	"strconv"
+	"time"
+
+	"gomon/pb"
+)
+
+// Sample units used by the v1 conversion
+const (
+	UnitPercent        = "percent"
+	UnitBytes          = "bytes"
+	UnitBytesPerSecond = "bytes_per_second"
+	UnitSeconds        = "seconds"
+	UnitMilliseconds   = "milliseconds"
+	UnitMegabytes      = "megabytes"
+	UnitPerSecond      = "per_second"
+)
+
+// FromV1 maps the fixed fields of a v1 Metric to generic samples, with the
+// series names and labels the aggregator has always written
+func FromV1(metric *pb.Metric) *pb.MetricBatch {
+	batch := &pb.MetricBatch{
+		SchemaVersion:            V2,
+		Hostname:                 metric.Hostname,
+		Host:                     metric.Host,
+		Labels:                   metric.Labels,
+		CorrelationId:            metric.CorrelationId,
+		TraceStartTimeMs:         millis(metric.TraceStartTime),
+		KafkaPublishTimeMs:       millis(metric.KafkaPublishTime),
+		AggregatorReceivedTimeMs: millis(metric.AggregatorReceivedTime),
+		VmPublishTimeMs:          millis(metric.VmPublishTime),
+	}
+
+	seconds, _ := strconv.ParseInt(metric.Timestamp, 10, 64)
+	timestamp := seconds * 1000
+
+	add := func(name string, kind pb.Sample_Type, unit string, value float64, labels map[string]string) {
+		batch.Samples = append(batch.Samples, &pb.Sample{
+			Name:        name,
+			Labels:      labels,
+			Type:        kind,
+			Unit:        unit,
+			TimestampMs: timestamp,
+			Value:       value,
+		})
+	}
+	gauge := func(name, unit string, value float64, labels map[string]string) {
+		add(name, pb.Sample_GAUGE, unit, value, labels)
+	}
+	counter := func(name, unit string, value float64, labels map[string]string) {
+		add(name, pb.Sample_COUNTER, unit, value, labels)
+	}
+
+	// CPU metric
+	if metric.CpuUsagePercent > 0 {
+		gauge("cpu_usage_percent", UnitPercent, float64(metric.CpuUsagePercent), nil)
+	}
+
+	// CPU breakdown and load averages
+	if cpu := metric.CpuStats; cpu != nil {
+		if cpu.Total != nil {
+			addCPUModes("cpu_mode_percent", cpu.Total, nil, gauge)
+		}
+		for _, core := range cpu.Cores {
+			labels := map[string]string{"cpu": core.Cpu}
+			gauge("cpu_core_usage_percent", UnitPercent, core.UsagePercent, labels)
+			addCPUModes("cpu_core_mode_percent", core, labels, gauge)
+		}
+		gauge("load_average_1m", "", cpu.Load1, nil)
+		gauge("load_average_5m", "", cpu.Load5, nil)
+		gauge("load_average_15m", "", cpu.Load15, nil)
+	}
+
+	// Memory metric
+	if metric.MemoryUsedPercent > 0 {
+		gauge("mem_usage_percent", UnitPercent, float64(metric.MemoryUsedPercent), nil)
+	}
+
+	// Memory stats, older agents only send the whole GB fields
+	if mem := metric.MemoryStats; mem != nil {
+		gauge("mem_total_bytes", UnitBytes, float64(mem.TotalBytes), nil)
+		gauge("mem_used_bytes", UnitBytes, float64(mem.UsedBytes), nil)
+		gauge("mem_free_bytes", UnitBytes, float64(mem.FreeBytes), nil)
+		gauge("mem_available_bytes", UnitBytes, float64(mem.AvailableBytes), nil)
+		gauge("mem_buffers_bytes", UnitBytes, float64(mem.BuffersBytes), nil)
+		gauge("mem_cached_bytes", UnitBytes, float64(mem.CachedBytes), nil)
+		gauge("mem_committed_bytes", UnitBytes, float64(mem.CommittedBytes), nil)
+		gauge("swap_total_bytes", UnitBytes, float64(mem.SwapTotalBytes), nil)
+		gauge("swap_used_bytes", UnitBytes, float64(mem.SwapUsedBytes), nil)
+		gauge("swap_free_bytes", UnitBytes, float64(mem.SwapFreeBytes), nil)
+		gauge("swap_used_percent", UnitPercent, mem.SwapUsedPercent, nil)
+	} else if metric.MemoryUsedGb > 0 {
+		gauge("mem_used_bytes", UnitBytes, float64(metric.MemoryUsedGb<<30), nil)
+	}
+
+	// Disk stats
+	for _, disk := range metric.DiskStats {
+		if disk == nil {
+			continue
+		}
+		labels := map[string]string{"mountpoint": disk.Mountpoint}
+		gauge("disk_used_percent", UnitPercent, float64(disk.UsedPercent), labels)
+		if disk.InodesTotal > 0 {
+			gauge("disk_inodes_used_percent", UnitPercent, disk.InodesUsedPercent, labels)
+			gauge("disk_inodes_free", "", float64(disk.InodesFree), labels)
+		}
+	}
+
+	// Disk I/O stats
+	for _, io := range metric.DiskIoStats {
+		if io == nil {
+			continue
+		}
+		labels := map[string]string{"device": io.Device}
+
+		// Raw counters
+		counter("disk_read_bytes_total", UnitBytes, float64(io.ReadBytes), labels)
+		counter("disk_write_bytes_total", UnitBytes, float64(io.WriteBytes), labels)
+		counter("disk_reads_total", "", float64(io.ReadCount), labels)
+		counter("disk_writes_total", "", float64(io.WriteCount), labels)
+		gauge("disk_io_in_progress", "", float64(io.IoInProgress), labels)
+
+		if io.RateIntervalSeconds > 0 {
+			gauge("disk_read_bytes_per_sec", UnitBytesPerSecond, io.ReadBytesPerSec, labels)
+			gauge("disk_write_bytes_per_sec", UnitBytesPerSecond, io.WriteBytesPerSec, labels)
+			gauge("disk_read_iops", UnitPerSecond, io.ReadIops, labels)
+			gauge("disk_write_iops", UnitPerSecond, io.WriteIops, labels)
+			gauge("disk_await_ms", UnitMilliseconds, io.AvgAwaitMs, labels)
+			gauge("disk_read_await_ms", UnitMilliseconds, io.ReadAwaitMs, labels)
+			gauge("disk_write_await_ms", UnitMilliseconds, io.WriteAwaitMs, labels)
+			gauge("disk_utilization_percent", UnitPercent, io.UtilizationPercent, labels)
+		}
+	}
+
+	// Network stats
+	for _, net := range metric.NetStats {
+		if net == nil {
+			continue
+		}
+		labels := map[string]string{"interface": net.InterfaceName}
+
+		// Raw counters
+		counter("int_bytes_recv_mb", UnitMegabytes, float64(net.BytesReceived>>20), labels)
+		counter("int_bytes_sent_mb", UnitMegabytes, float64(net.BytesSent>>20), labels)
+		counter("net_packets_recv_total", "", float64(net.PacketsReceived), labels)
+		counter("net_packets_sent_total", "", float64(net.PacketsSent), labels)
+		counter("net_errors_in_total", "", float64(net.ErrorsIn), labels)
+		counter("net_errors_out_total", "", float64(net.ErrorsOut), labels)
+		counter("net_drops_in_total", "", float64(net.DropsIn), labels)
+		counter("net_drops_out_total", "", float64(net.DropsOut), labels)
+
+		// Rates are only present once the agent has a previous snapshot
+		if net.RateIntervalSeconds > 0 {
+			gauge("net_bytes_recv_per_sec", UnitBytesPerSecond, net.BytesReceivedPerSec, labels)
+			gauge("net_bytes_sent_per_sec", UnitBytesPerSecond, net.BytesSentPerSec, labels)
+			gauge("net_packets_recv_per_sec", UnitPerSecond, net.PacketsReceivedPerSec, labels)
+			gauge("net_packets_sent_per_sec", UnitPerSecond, net.PacketsSentPerSec, labels)
+			gauge("net_errors_in_per_sec", UnitPerSecond, net.ErrorsInPerSec, labels)
+			gauge("net_errors_out_per_sec", UnitPerSecond, net.ErrorsOutPerSec, labels)
+			gauge("net_drops_in_per_sec", UnitPerSecond, net.DropsInPerSec, labels)
+			gauge("net_drops_out_per_sec", UnitPerSecond, net.DropsOutPerSec, labels)
+		}
+	}
+
+	// Top processes, only the ones the agent reported this cycle. A process is
+	// its name and user: the pid would start a new series on every restart.
+	// Processes sharing all labels, e.g. worker pools, are summed.
+	type processKey struct{ name, user, cmdlineHash string }
+	var order []processKey
+	processes := make(map[processKey]*pb.ProcessUsage)
+	counts := make(map[processKey]int)
+	for _, proc := range metric.ProcessStats {
+		if proc == nil {
+			continue
+		}
+		key := processKey{proc.Name, proc.User, proc.CmdlineHash}
+		sum := processes[key]
+		if sum == nil {
+			sum = &pb.ProcessUsage{}
+			processes[key] = sum
+			order = append(order, key)
+		}
+		sum.CpuPercent += proc.CpuPercent
+		sum.RssBytes += proc.RssBytes
+		sum.OpenFds += proc.OpenFds
+		sum.Threads += proc.Threads
+		counts[key]++
+	}
+	for _, key := range order {
+		proc := processes[key]
+		labels := map[string]string{
+			"process": key.name,
+			"user":    key.user,
+			// Opt-in through the aggregator label policy
+			"cmdline_hash": key.cmdlineHash,
+		}
+		gauge("process_cpu_percent", UnitPercent, proc.CpuPercent, labels)
+		gauge("process_rss_bytes", UnitBytes, float64(proc.RssBytes), labels)
+		gauge("process_open_fds", "", float64(proc.OpenFds), labels)
+		gauge("process_threads", "", float64(proc.Threads), labels)
+		gauge("process_count", "", float64(counts[key]), labels)
+	}
+
+	// Containers, labelled with the pod when the agent could tell
+	for _, container := range metric.ContainerStats {
+		if container == nil {
+			continue
+		}
+		labels := map[string]string{"container_id": container.ContainerId}
+		if container.PodUid != "" {
+			labels["pod_uid"] = container.PodUid
+		}
+
+		counter("container_cpu_usage_seconds_total", UnitSeconds, container.CpuUsageSeconds, labels)
+		counter("container_cpu_periods_total", "", float64(container.CpuPeriods), labels)
+		counter("container_cpu_throttled_periods_total", "", float64(container.CpuThrottledPeriods), labels)
+		counter("container_cpu_throttled_seconds_total", UnitSeconds, container.CpuThrottledSeconds, labels)
+		gauge("container_memory_usage_bytes", UnitBytes, float64(container.MemoryUsageBytes), labels)
+		gauge("container_memory_working_set_bytes", UnitBytes, float64(container.MemoryWorkingSetBytes), labels)
+		counter("container_oom_kills_total", "", float64(container.OomKills), labels)
+		counter("container_io_read_bytes_total", UnitBytes, float64(container.IoReadBytes), labels)
+		counter("container_io_write_bytes_total", UnitBytes, float64(container.IoWriteBytes), labels)
+		counter("container_io_reads_total", "", float64(container.IoReads), labels)
+		counter("container_io_writes_total", "", float64(container.IoWrites), labels)
+
+		// Limits are only meaningful when set
+		if container.CpuLimitCores > 0 {
+			gauge("container_cpu_limit_cores", "", container.CpuLimitCores, labels)
+		}
+		if container.MemoryLimitBytes > 0 {
+			gauge("container_memory_limit_bytes", UnitBytes, float64(container.MemoryLimitBytes), labels)
+		}
+
+		if container.RateIntervalSeconds > 0 {
+			gauge("container_cpu_usage_percent", UnitPercent, container.CpuUsagePercent, labels)
+			gauge("container_io_read_bytes_per_sec", UnitBytesPerSecond, container.IoReadBytesPerSec, labels)
+			gauge("container_io_write_bytes_per_sec", UnitBytesPerSecond, container.IoWriteBytesPerSec, labels)
+		}
+	}
+
+	// Pressure stall information
+	for _, psi := range metric.PressureStats {
+		if psi == nil {
+			continue
+		}
+		labels := map[string]string{"resource": psi.Resource, "kind": psi.Kind}
+		gauge("pressure_avg10_percent", UnitPercent, psi.Avg10, labels)
+		gauge("pressure_avg60_percent", UnitPercent, psi.Avg60, labels)
+		gauge("pressure_avg300_percent", UnitPercent, psi.Avg300, labels)
+		counter("pressure_stalled_seconds_total", UnitSeconds, psi.TotalSeconds, labels)
+	}
+
+	return batch
+}
+
+// addCPUModes emits one sample per CPU mode, labelled mode="user|system|..."
+func addCPUModes(name string, times *pb.CpuTimesPercent, labels map[string]string, gauge func(string, string, float64, map[string]string)) {
+	modes := []struct {
+		mode  string
+		value float64
+	}{
+		{"user", times.UserPercent},
+		{"system", times.SystemPercent},
+		{"idle", times.IdlePercent},
+		{"nice", times.NicePercent},
+		{"iowait", times.IowaitPercent},
+		{"irq", times.IrqPercent},
+		{"softirq", times.SoftirqPercent},
+		{"steal", times.StealPercent},
+	}
+	for _, m := range modes {
+		modeLabels := map[string]string{"mode": m.mode}
+		for k, v := range labels {
+			modeLabels[k] = v
+		}
+		gauge(name, UnitPercent, m.value, modeLabels)
+	}
+}
+
+// millis converts a v1 RFC 3339 pipeline timestamp, 0 when unset or invalid
+func millis(value string) int64 {
+	if value == "" {
+		return 0
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
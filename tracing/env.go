@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// GetConfig returns the defaults for service overridden by the TRACING_* variables
+func GetConfig(service string) (Config, error) {
+	c := DefaultConfig(service)
+	if err := c.ApplyEnv(); err != nil {
+		return Config{}, err
+	}
+	return c, c.Validate()
+}
+
+// ApplyEnv overrides c with the TRACING_* and SERVICE_VERSION variables that are set
+func (c *Config) ApplyEnv() error {
+	bools := map[string]*bool{
+		"TRACING_ENABLED":              &c.Enabled,
+		"TRACING_INSECURE":             &c.Insecure,
+		"TRACING_SAMPLER_PARENT_BASED": &c.Sampler.ParentBased,
+	}
+	for name, field := range bools {
+		if v := os.Getenv(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("could not parse %s: %w", name, err)
+			}
+			*field = b
+		}
+	}
+
+	values := map[string]*string{
+		"TRACING_ENDPOINT": &c.Endpoint,
+		"TRACING_PROTOCOL": &c.Protocol,
+		"SERVICE_VERSION":  &c.ServiceVersion,
+	}
+	for name, field := range values {
+		if v := os.Getenv(name); v != "" {
+			*field = v
+		}
+	}
+
+	if v := os.Getenv("TRACING_SAMPLER_RATIO"); v != "" {
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("could not parse TRACING_SAMPLER_RATIO: %w", err)
+		}
+		c.Sampler.Ratio = ratio
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Package tracing sets up OpenTelemetry tracing with OTLP export for the gomon services
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"runtime/debug"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Config describes where spans are exported and which traces are kept.
+// The zero value with Enabled false only installs the propagators.
+type Config struct {
+	Enabled        bool   `yaml:"enabled"`
+	ServiceName    string `yaml:"service_name"`
+	ServiceVersion string `yaml:"service_version"`
+
+	// Endpoint is host:port or a URL, the OTLP defaults are 4317 for grpc and 4318 for http
+	Endpoint string `yaml:"endpoint"`
+	// Protocol is "grpc" or "http"
+	Protocol string `yaml:"protocol"`
+	// Insecure disables TLS for a host:port endpoint, URLs use their scheme
+	Insecure bool `yaml:"insecure"`
+
+	Sampler SamplerConfig `yaml:"sampler"`
+
+	// Extra resource attributes, OTEL_RESOURCE_ATTRIBUTES is applied on top
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+}
+
+type SamplerConfig struct {
+	// Ratio of new traces kept, 1 keeps all and 0 none
+	Ratio float64 `yaml:"ratio"`
+	// ParentBased follows the decision of the caller for traces started upstream,
+	// so a trace is never half recorded across services
+	ParentBased bool `yaml:"parent_based"`
+}
+
+// DefaultConfig exports every trace of service to the in-cluster collector over OTLP/gRPC
+func DefaultConfig(service string) Config {
+	return Config{
+		Enabled:     true,
+		ServiceName: service,
+		Endpoint:    "jaeger:4317",
+		Protocol:    "grpc",
+		Insecure:    true,
+		Sampler: SamplerConfig{
+			Ratio:       1,
+			ParentBased: true,
+		},
+	}
+}
+
+// Validate reports settings Init would reject
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ServiceName == "" {
+		return errors.New("tracing.service_name must be set when tracing is enabled")
+	}
+	switch strings.ToLower(c.Protocol) {
+	case "grpc", "http":
+	default:
+		return fmt.Errorf("tracing.protocol: unknown protocol %q (want grpc or http)", c.Protocol)
+	}
+	if err := validateEndpoint(c.Endpoint); err != nil {
+		return fmt.Errorf("tracing.endpoint: %w", err)
+	}
+	if c.Sampler.Ratio < 0 || c.Sampler.Ratio > 1 {
+		return fmt.Errorf("tracing.sampler.ratio must be between 0 and 1, got %g", c.Sampler.Ratio)
+	}
+	return nil
+}
+
+func validateEndpoint(endpoint string) error {
+	if strings.Contains(endpoint, "://") {
+		_, err := url.ParseRequestURI(endpoint)
+		return err
+	}
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return fmt.Errorf("want host:port or a URL: %w", err)
+	}
+	return nil
+}
+
+// Init installs the global tracer provider and the W3C trace context propagator.
+// The returned shutdown flushes pending spans and must be called before exit.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OTLP exporter for %s: %w", cfg.ServiceName, err)
+	}
+
+	res, err := newResource(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build tracing resource for %s: %w", cfg.ServiceName, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(Sampler(cfg.Sampler)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Sampler maps the sampler settings to an SDK sampler
+func Sampler(cfg SamplerConfig) sdktrace.Sampler {
+	sampler := sdktrace.TraceIDRatioBased(cfg.Ratio)
+	if cfg.ParentBased {
+		return sdktrace.ParentBased(sampler)
+	}
+	return sampler
+}
+
+func newExporter(ctx context.Context, cfg Config) (*otlptrace.Exporter, error) {
+	isURL := strings.Contains(cfg.Endpoint, "://")
+
+	if strings.ToLower(cfg.Protocol) == "http" {
+		opts := []otlptracehttp.Option{}
+		if isURL {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{}
+	if isURL {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newResource describes the process: service name and version, host, and any extra attributes
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(serviceVersion(cfg.ServiceVersion)),
+	}
+	for key, value := range cfg.ResourceAttributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	return resource.New(ctx,
+		resource.WithHost(),
+		resource.WithAttributes(attrs...),
+		// OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME win over the config
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithProcessPID(),
+	)
+}
+
+// serviceVersion falls back to the module version the binary was built from
+func serviceVersion(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
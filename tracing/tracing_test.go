@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestGetConfigFromEnv(t *testing.T) {
+	t.Setenv("TRACING_ENDPOINT", "https://otel-collector:4318")
+	t.Setenv("TRACING_PROTOCOL", "http")
+	t.Setenv("TRACING_SAMPLER_RATIO", "0.25")
+	t.Setenv("TRACING_SAMPLER_PARENT_BASED", "false")
+	t.Setenv("SERVICE_VERSION", "1.2.3")
+
+	cfg, err := GetConfig("gomon-test")
+	if err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+	if cfg.ServiceName != "gomon-test" || cfg.Endpoint != "https://otel-collector:4318" || cfg.Protocol != "http" {
+		t.Errorf("Unexpected exporter settings: %+v", cfg)
+	}
+	if cfg.Sampler.Ratio != 0.25 || cfg.Sampler.ParentBased || cfg.ServiceVersion != "1.2.3" {
+		t.Errorf("Unexpected sampler or version: %+v", cfg)
+	}
+
+	t.Setenv("TRACING_SAMPLER_RATIO", "half")
+	if _, err := GetConfig("gomon-test"); err == nil {
+		t.Error("Expected error for an unparsable ratio")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := DefaultConfig("gomon-test")
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Default config must be valid: %v", err)
+	}
+
+	cases := map[string]func(*Config){
+		"missing service":  func(c *Config) { c.ServiceName = "" },
+		"unknown protocol": func(c *Config) { c.Protocol = "thrift" },
+		"missing port":     func(c *Config) { c.Endpoint = "jaeger" },
+		"ratio above one":  func(c *Config) { c.Sampler.Ratio = 1.5 },
+	}
+	for name, mutate := range cases {
+		cfg := DefaultConfig("gomon-test")
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		// Disabled tracing is never rejected
+		cfg.Enabled = false
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%s: disabled config rejected: %v", name, err)
+		}
+	}
+}
+
+func TestSamplerFollowsParent(t *testing.T) {
+	sampler := Sampler(SamplerConfig{Ratio: 0, ParentBased: true})
+
+	sampledParent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: sampledParent, TraceID: trace.TraceID{1}})
+	if result.Decision != sdktrace.RecordAndSample {
+		t.Errorf("Expected a sampled parent to be followed, got %v", result.Decision)
+	}
+
+	result = sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background(), TraceID: trace.TraceID{1}})
+	if result.Decision != sdktrace.Drop {
+		t.Errorf("Expected new traces dropped at ratio 0, got %v", result.Decision)
+	}
+}
+
+func TestInitAndShutdown(t *testing.T) {
+	for _, protocol := range []string{"grpc", "http"} {
+		cfg := DefaultConfig("gomon-test")
+		cfg.Protocol = protocol
+		cfg.Endpoint = "127.0.0.1:1"
+		cfg.ResourceAttributes = map[string]string{"deployment.environment": "test"}
+
+		// Exporters connect lazily, so Init succeeds without a collector
+		shutdown, err := Init(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("%s: Init failed: %v", protocol, err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("%s: shutdown failed: %v", protocol, err)
+		}
+	}
+}